@@ -1,31 +1,246 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config contiene toda la configuración de la aplicación
 type Config struct {
-	Server   ServerConfig
-	JWT      JWTConfig
-	Users    UsersServiceConfig
-	Postgres PostgresConfig
+	Server        ServerConfig
+	JWT           JWTConfig
+	Users         UsersServiceConfig
+	Postgres      PostgresConfig
+	Introspection IntrospectionConfig
+	OAuth         OAuthConfig
+	Registration  RegistrationConfig
+	Mail          MailConfig
+	Password      PasswordConfig
+	Lockout       LockoutConfig
+	RateLimit     RateLimitConfig
+	MFA           MFAConfig
+	WebAuthn      WebAuthnConfig
+	MagicLink     MagicLinkConfig
+	SMS           SMSConfig
+	Audit         AuditConfig
+	Webhook       WebhookConfig
 }
 
 // ServerConfig contiene la configuración del servidor HTTP
 type ServerConfig struct {
 	Port string
 	Host string
+	// PublicURL es la URL pública del servicio, usada en los documentos de descubrimiento y enlaces
+	PublicURL string
+	// TrustedProxies son las IPs o rangos CIDR de los proxies cuyas cabeceras
+	// X-Forwarded-For se aceptan; vacío significa usar siempre la IP de la conexión
+	TrustedProxies []string
 }
 
 // JWTConfig contiene la configuración de JWT
 type JWTConfig struct {
-	SecretKey              string
+	Issuer           string
+	ClientID         string
+	Scope            string
+	SigningAlgorithm string
+	SecretKey        string
+	PrivateKeyPath   string
+	// Claves retiradas que siguen verificando tokens durante KeyGracePeriod
+	RetiredSecretKeys      []string
+	RetiredPrivateKeyPaths []string
 	AccessTokenExpiration  time.Duration
 	RefreshTokenExpiration time.Duration
+	AccessTokenAudience    string
+	RefreshTokenAudience   string
+	// Intervalo de limpieza de revocaciones expiradas (horas)
+	RevocationCleanupInterval time.Duration
+	// Tiempo que una clave retirada sigue verificando tokens (horas, por defecto la expiración del refresh token)
+	KeyGracePeriod time.Duration
+	// KeyEncryptionKey cifra las claves generadas al rotar; si no se configura se deriva de MFA_ENCRYPTION_KEY
+	KeyEncryptionKey string
+	// Frecuencia con la que cada réplica recarga las claves de firma compartidas (segundos)
+	KeyRefreshInterval time.Duration
+}
+
+// IntrospectionConfig contiene los resource servers autorizados a usar el endpoint de introspección
+type IntrospectionConfig struct {
+	// Clients mapea client_id -> client_secret
+	Clients map[string]string
+}
+
+// RegistrationConfig contiene la configuración del registro de usuarios
+type RegistrationConfig struct {
+	// IssueTokens inicia sesión tras el registro devolviendo un par de tokens
+	IssueTokens bool
+	// RequireVerifiedEmail impide el login con contraseña hasta verificar el email
+	RequireVerifiedEmail bool
+	// EmailVerificationURL es la página a la que apunta el enlace de verificación
+	EmailVerificationURL string
+	// Validez del enlace de verificación (horas)
+	EmailVerificationTTL time.Duration
+}
+
+// PasswordConfig contiene la configuración de la recuperación de contraseña
+type PasswordConfig struct {
+	// ResetURL es la página del frontend a la que apunta el enlace de recuperación
+	ResetURL string
+	// Validez del enlace de recuperación (horas)
+	ResetTokenTTL time.Duration
+	// Antigüedad máxima de la autenticación para establecer la primera contraseña (minutos)
+	RecentAuthMaxAge time.Duration
+	// HashAlgorithm es "argon2id" o "bcrypt"; los hashes de otro algoritmo se regeneran en el login
+	HashAlgorithm string
+	BcryptCost    int
+	// Parámetros de argon2id: memoria en KiB, iteraciones e hilos
+	Argon2Memory      int
+	Argon2Time        int
+	Argon2Parallelism int
+}
+
+// LockoutConfig contiene la política de bloqueo de cuentas tras logins fallidos
+type LockoutConfig struct {
+	// Threshold es el número de fallos consecutivos a partir del cual se bloquea la cuenta
+	Threshold int
+	// Duración del primer bloqueo, que se duplica con cada fallo adicional (minutos)
+	BaseDelay time.Duration
+	// Duración máxima de un bloqueo (minutos)
+	MaxDelay time.Duration
+	// Tiempo sin fallos tras el que se reinicia el contador (horas)
+	ResetAfter time.Duration
+}
+
+// RateLimitConfig contiene los límites de peticiones de los endpoints públicos de autenticación
+type RateLimitConfig struct {
+	// Backend es "memory", "postgres" (compartido entre réplicas) o "none"
+	Backend string
+	// Peticiones admitidas por Period para cada IP, cuenta y ruta; 0 deshabilita ese límite
+	PerIP      int
+	PerAccount int
+	PerRoute   int
+	// Periodo en que se recargan los límites (minutos)
+	Period time.Duration
+}
+
+// MFAConfig contiene la configuración del segundo factor TOTP
+type MFAConfig struct {
+	// Issuer es el nombre de la cuenta que muestran las apps de autenticación
+	Issuer string
+	// EncryptionKey es la clave AES-256 (32 bytes en base64) con la que se cifran los secretos TOTP
+	EncryptionKey string
+	// Duración del challenge que devuelve el login cuando se requiere segundo factor (minutos)
+	ChallengeTTL time.Duration
+}
+
+// WebAuthnConfig contiene la configuración del relying party para passkeys
+type WebAuthnConfig struct {
+	// RPID es el dominio al que quedan ligadas las passkeys, sin esquema ni puerto (p. ej. bikes2road.com)
+	RPID          string
+	RPDisplayName string
+	// RPOrigins son los orígenes de las apps desde los que se registran y usan las passkeys
+	RPOrigins []string
+	// Attestation es none (por defecto), indirect, direct o enterprise
+	Attestation string
+	// Tiempo máximo entre el inicio y el final de una ceremonia (minutos)
+	SessionTTL time.Duration
+}
+
+// MagicLinkConfig contiene la configuración del login sin contraseña con enlaces enviados por email
+type MagicLinkConfig struct {
+	// URL es la dirección a la que apunta el enlace de login
+	URL string
+	// Validez del enlace (minutos)
+	TTL time.Duration
+	// Enlaces admitidos por cada email durante TTL; 0 deshabilita el límite
+	PerEmail int
+	// SigningKey es la clave HMAC (32 bytes en base64) con la que se firman los enlaces.
+	// Si no se configura se deriva de MFA_ENCRYPTION_KEY con HKDF
+	SigningKey string
+}
+
+// SMSConfig contiene la configuración del login con códigos enviados por SMS
+type SMSConfig struct {
+	// Driver es "log" (desarrollo local: no envía nada) o "webhook"
+	Driver string
+	// WebhookURL recibe un POST JSON {"to", "body"} por cada SMS
+	WebhookURL string
+	// WebhookToken se envía como Authorization: Bearer
+	WebhookToken string
+	// Validez de cada código (minutos)
+	CodeTTL time.Duration
+	// Verificaciones admitidas por código
+	MaxAttempts int
+	// Códigos admitidos por cada teléfono durante CodeTTL; 0 deshabilita el límite
+	PerPhone int
+}
+
+// AuditConfig contiene la configuración del writer asíncrono del registro de auditoría
+type AuditConfig struct {
+	// Eventos pendientes de escribir que admite el buffer; los que no caben se escriben en el log
+	BufferSize int
+	// Eventos que se escriben en cada lote
+	BatchSize int
+	// Tiempo máximo que un evento espera en el buffer (segundos)
+	FlushInterval time.Duration
+}
+
+// WebhookConfig contiene la configuración de la entrega de eventos de seguridad por webhook
+type WebhookConfig struct {
+	// EncryptionKey es la clave AES-256 (32 bytes en base64) con la que se cifran los secretos de firma.
+	// Si no se configura se deriva de MFA_ENCRYPTION_KEY con HKDF, distinta de la que cifra los secretos TOTP
+	EncryptionKey string
+	// Intentos tras los que una entrega pasa a dead
+	MaxAttempts int
+	// Espera tras el primer fallo (segundos); se duplica en cada reintento
+	RetryBase time.Duration
+	// Espera máxima entre reintentos (minutos)
+	RetryMax time.Duration
+	// Timeout de cada petición (segundos)
+	Timeout time.Duration
+	// Frecuencia con la que se buscan entregas pendientes (segundos)
+	PollInterval time.Duration
+	// Entregas que se procesan en cada pasada
+	BatchSize int
+	// AllowInsecureURLs admite endpoints http (solo desarrollo)
+	AllowInsecureURLs bool
+}
+
+// MailConfig contiene la configuración del envío de emails
+type MailConfig struct {
+	// Driver es "smtp" o "log" (desarrollo local)
+	Driver string
+	From   string
+	// LogDir guarda los emails como ficheros .eml con el driver "log"; vacío los escribe en el log
+	LogDir       string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// OAuthConfig contiene la configuración de los proveedores de identidad externos
+type OAuthConfig struct {
+	Google OAuthProviderConfig
+	// DefaultRole es el rol de los usuarios creados automáticamente en su primer login
+	DefaultRole string
+}
+
+// OAuthProviderConfig contiene la configuración para verificar los ID tokens de un proveedor
+type OAuthProviderConfig struct {
+	// ClientIDs son las audiencias aceptadas; si está vacío el proveedor queda deshabilitado
+	ClientIDs []string
+	JWKSURL   string
+	Issuers   []string
+	// AutoProvision crea el usuario en su primer login si no existe una cuenta con su email
+	AutoProvision bool
+	// AllowedEmailDomains restringe el alta automática a esos dominios; vacío permite cualquiera
+	AllowedEmailDomains []string
 }
 
 // UsersServiceConfig contiene la configuración del servicio de usuarios
@@ -45,15 +260,32 @@ type PostgresConfig struct {
 
 // Load carga la configuración desde variables de entorno
 func Load() (*Config, error) {
+	refreshTokenExpiration := getDurationEnv("JWT_REFRESH_TOKEN_EXPIRATION", 24*time.Hour)
+
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8084"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:           getEnv("PORT", "8084"),
+			Host:           getEnv("SERVER_HOST", "0.0.0.0"),
+			PublicURL:      getEnv("PUBLIC_BASE_URL", "http://localhost:8084"),
+			TrustedProxies: getListEnv("TRUSTED_PROXIES"),
 		},
 		JWT: JWTConfig{
-			SecretKey:              getEnv("JWT_SECRET_KEY", ""),
-			AccessTokenExpiration:  getDurationEnv("JWT_ACCESS_TOKEN_EXPIRATION", 24*time.Hour),
-			RefreshTokenExpiration: getDurationEnv("JWT_REFRESH_TOKEN_EXPIRATION", 24*time.Hour),
+			Issuer:                    getEnv("JWT_ISSUER", "bikes2road-auth"),
+			ClientID:                  getEnv("JWT_CLIENT_ID", "bikes2road-app"),
+			Scope:                     getEnv("JWT_SCOPE", "openid profile email"),
+			SigningAlgorithm:          getEnv("JWT_SIGNING_ALGORITHM", "HS256"),
+			SecretKey:                 getEnv("JWT_SECRET_KEY", ""),
+			PrivateKeyPath:            getEnv("JWT_PRIVATE_KEY_PATH", ""),
+			RetiredSecretKeys:         getListEnv("JWT_RETIRED_SECRET_KEYS"),
+			RetiredPrivateKeyPaths:    getListEnv("JWT_RETIRED_PRIVATE_KEY_PATHS"),
+			AccessTokenExpiration:     getDurationEnv("JWT_ACCESS_TOKEN_EXPIRATION", 24*time.Hour),
+			RefreshTokenExpiration:    refreshTokenExpiration,
+			AccessTokenAudience:       getEnv("JWT_ACCESS_TOKEN_AUDIENCE", "bikes2road-api"),
+			RefreshTokenAudience:      getEnv("JWT_REFRESH_TOKEN_AUDIENCE", "bikes2road-auth-refresh"),
+			RevocationCleanupInterval: getDurationEnv("JWT_REVOCATION_CLEANUP_INTERVAL", time.Hour),
+			KeyGracePeriod:            getDurationEnv("JWT_KEY_GRACE_PERIOD", refreshTokenExpiration),
+			KeyEncryptionKey:          getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
+			KeyRefreshInterval:        getSecondsEnv("JWT_KEY_REFRESH_INTERVAL", time.Minute),
 		},
 		Postgres: PostgresConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -66,16 +298,243 @@ func Load() (*Config, error) {
 		Users: UsersServiceConfig{
 			BaseURL: getEnv("USERS_SERVICE_URL", "http://localhost:8083"),
 		},
+		Introspection: IntrospectionConfig{
+			Clients: getMapEnv("INTROSPECTION_CLIENTS"),
+		},
+		OAuth: OAuthConfig{
+			Google: OAuthProviderConfig{
+				ClientIDs:           getListEnv("GOOGLE_CLIENT_IDS"),
+				JWKSURL:             getEnv("GOOGLE_JWKS_URL", "https://www.googleapis.com/oauth2/v3/certs"),
+				Issuers:             getListEnv("GOOGLE_ISSUERS"),
+				AutoProvision:       getBoolEnv("GOOGLE_AUTO_PROVISION", false),
+				AllowedEmailDomains: getListEnv("GOOGLE_ALLOWED_EMAIL_DOMAINS"),
+			},
+			DefaultRole: getEnv("OAUTH_DEFAULT_ROLE", "user"),
+		},
+		Registration: RegistrationConfig{
+			IssueTokens:          getBoolEnv("REGISTER_ISSUE_TOKENS", true),
+			RequireVerifiedEmail: getBoolEnv("REQUIRE_VERIFIED_EMAIL", false),
+			EmailVerificationURL: getEnv("EMAIL_VERIFICATION_URL", ""),
+			EmailVerificationTTL: getDurationEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+		},
+		Password: PasswordConfig{
+			ResetURL:          getEnv("PASSWORD_RESET_URL", ""),
+			ResetTokenTTL:     getDurationEnv("PASSWORD_RESET_TTL", time.Hour),
+			RecentAuthMaxAge:  getMinutesEnv("PASSWORD_SET_MAX_AUTH_AGE", 10*time.Minute),
+			HashAlgorithm:     getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getIntEnv("BCRYPT_COST", 10),
+			Argon2Memory:      getIntEnv("ARGON2_MEMORY", 64*1024),
+			Argon2Time:        getIntEnv("ARGON2_TIME", 3),
+			Argon2Parallelism: getIntEnv("ARGON2_PARALLELISM", 2),
+		},
+		Lockout: LockoutConfig{
+			Threshold:  getIntEnv("LOGIN_LOCKOUT_THRESHOLD", 5),
+			BaseDelay:  getMinutesEnv("LOGIN_LOCKOUT_BASE_DELAY", time.Minute),
+			MaxDelay:   getMinutesEnv("LOGIN_LOCKOUT_MAX_DELAY", time.Hour),
+			ResetAfter: getDurationEnv("LOGIN_LOCKOUT_RESET_AFTER", 24*time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Backend:    getEnv("RATE_LIMIT_BACKEND", "memory"),
+			PerIP:      getIntEnv("RATE_LIMIT_PER_IP", 30),
+			PerAccount: getIntEnv("RATE_LIMIT_PER_ACCOUNT", 10),
+			PerRoute:   getIntEnv("RATE_LIMIT_PER_ROUTE", 0),
+			Period:     getMinutesEnv("RATE_LIMIT_PERIOD", time.Minute),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Bikes2Road"),
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", ""),
+			ChallengeTTL:  getMinutesEnv("MFA_CHALLENGE_TTL", 5*time.Minute),
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:          getEnv("WEBAUTHN_RP_ID", "localhost"),
+			RPDisplayName: getEnv("WEBAUTHN_RP_NAME", "Bikes2Road"),
+			RPOrigins:     getListEnv("WEBAUTHN_RP_ORIGINS"),
+			Attestation:   getEnv("WEBAUTHN_ATTESTATION", "none"),
+			SessionTTL:    getMinutesEnv("WEBAUTHN_SESSION_TTL", 5*time.Minute),
+		},
+		MagicLink: MagicLinkConfig{
+			URL:        getEnv("MAGIC_LINK_URL", ""),
+			TTL:        getMinutesEnv("MAGIC_LINK_TTL", 15*time.Minute),
+			PerEmail:   getIntEnv("MAGIC_LINK_PER_EMAIL", 3),
+			SigningKey: getEnv("MAGIC_LINK_SIGNING_KEY", ""),
+		},
+		SMS: SMSConfig{
+			Driver:       getEnv("SMS_DRIVER", "log"),
+			WebhookURL:   getEnv("SMS_WEBHOOK_URL", ""),
+			WebhookToken: getEnv("SMS_WEBHOOK_TOKEN", ""),
+			CodeTTL:      getMinutesEnv("SMS_CODE_TTL", 5*time.Minute),
+			MaxAttempts:  getIntEnv("SMS_CODE_MAX_ATTEMPTS", 5),
+			PerPhone:     getIntEnv("SMS_PER_PHONE", 3),
+		},
+		Audit: AuditConfig{
+			BufferSize:    getIntEnv("AUDIT_BUFFER_SIZE", 1000),
+			BatchSize:     getIntEnv("AUDIT_BATCH_SIZE", 100),
+			FlushInterval: getSecondsEnv("AUDIT_FLUSH_INTERVAL", 2*time.Second),
+		},
+		Webhook: WebhookConfig{
+			EncryptionKey:     getEnv("WEBHOOK_ENCRYPTION_KEY", ""),
+			MaxAttempts:       getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBase:         getSecondsEnv("WEBHOOK_RETRY_BASE", 30*time.Second),
+			RetryMax:          getMinutesEnv("WEBHOOK_RETRY_MAX", time.Hour),
+			Timeout:           getSecondsEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval:      getSecondsEnv("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			BatchSize:         getIntEnv("WEBHOOK_BATCH_SIZE", 20),
+			AllowInsecureURLs: getBoolEnv("WEBHOOK_ALLOW_INSECURE_URLS", false),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Bikes2Road <no-reply@bikes2road.com>"),
+			LogDir:       getEnv("MAIL_LOG_DIR", ""),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
 	}
 
 	// Validar configuración requerida
-	if config.JWT.SecretKey == "" {
-		return nil, fmt.Errorf("JWT_SECRET_KEY is required")
+	switch config.JWT.SigningAlgorithm {
+	case "HS256":
+		if config.JWT.SecretKey == "" {
+			return nil, fmt.Errorf("JWT_SECRET_KEY is required")
+		}
+	case "RS256", "ES256", "EdDSA":
+		if config.JWT.PrivateKeyPath == "" {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_PATH is required for %s", config.JWT.SigningAlgorithm)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported JWT_SIGNING_ALGORITHM %q", config.JWT.SigningAlgorithm)
+	}
+	if config.Registration.EmailVerificationURL == "" {
+		config.Registration.EmailVerificationURL = strings.TrimRight(config.Server.PublicURL, "/") + "/v1/email/verify"
+	}
+	if config.Password.ResetURL == "" {
+		config.Password.ResetURL = strings.TrimRight(config.Server.PublicURL, "/") + "/reset-password"
+	}
+	switch config.Password.HashAlgorithm {
+	case "argon2id":
+		if config.Password.Argon2Memory <= 0 || config.Password.Argon2Time <= 0 ||
+			config.Password.Argon2Parallelism <= 0 || config.Password.Argon2Parallelism > 255 {
+			return nil, fmt.Errorf("ARGON2_MEMORY, ARGON2_TIME and ARGON2_PARALLELISM must be positive (parallelism up to 255)")
+		}
+	case "bcrypt":
+	default:
+		return nil, fmt.Errorf("unsupported PASSWORD_HASH_ALGORITHM %q", config.Password.HashAlgorithm)
+	}
+	if config.Lockout.Threshold <= 0 || config.Lockout.BaseDelay <= 0 || config.Lockout.MaxDelay < config.Lockout.BaseDelay {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD and LOGIN_LOCKOUT_BASE_DELAY must be positive and LOGIN_LOCKOUT_MAX_DELAY not lower than the base delay")
+	}
+	if config.MFA.EncryptionKey == "" {
+		return nil, fmt.Errorf("MFA_ENCRYPTION_KEY is required: generate one with `openssl rand -base64 32` (see the upgrade notes in the README)")
+	}
+	switch config.WebAuthn.Attestation {
+	case "none", "indirect", "direct", "enterprise":
+	default:
+		return nil, fmt.Errorf("unsupported WEBAUTHN_ATTESTATION %q", config.WebAuthn.Attestation)
+	}
+	if len(config.WebAuthn.RPOrigins) == 0 {
+		config.WebAuthn.RPOrigins = []string{strings.TrimRight(config.Server.PublicURL, "/")}
+	}
+	if config.MagicLink.URL == "" {
+		config.MagicLink.URL = strings.TrimRight(config.Server.PublicURL, "/") + "/v1/login/magic-link/verify"
+	}
+	if config.MagicLink.TTL <= 0 {
+		return nil, fmt.Errorf("MAGIC_LINK_TTL must be positive")
+	}
+	switch config.SMS.Driver {
+	case "log":
+	case "webhook":
+		if config.SMS.WebhookURL == "" {
+			return nil, fmt.Errorf("SMS_WEBHOOK_URL is required when SMS_DRIVER is webhook")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported SMS_DRIVER %q", config.SMS.Driver)
+	}
+	if config.SMS.CodeTTL <= 0 || config.SMS.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("SMS_CODE_TTL and SMS_CODE_MAX_ATTEMPTS must be positive")
+	}
+	if config.Audit.BufferSize <= 0 || config.Audit.BatchSize <= 0 || config.Audit.FlushInterval <= 0 {
+		return nil, fmt.Errorf("AUDIT_BUFFER_SIZE, AUDIT_BATCH_SIZE and AUDIT_FLUSH_INTERVAL must be positive")
+	}
+	if config.JWT.KeyEncryptionKey == "" {
+		key, err := deriveEncryptionKey(config.MFA.EncryptionKey, "signing-keys")
+		if err != nil {
+			return nil, fmt.Errorf("MFA_ENCRYPTION_KEY: %w", err)
+		}
+		config.JWT.KeyEncryptionKey = key
+	}
+	if config.JWT.KeyRefreshInterval <= 0 {
+		return nil, fmt.Errorf("JWT_KEY_REFRESH_INTERVAL must be positive")
+	}
+	if config.Webhook.EncryptionKey == "" {
+		key, err := deriveEncryptionKey(config.MFA.EncryptionKey, "webhook-secrets")
+		if err != nil {
+			return nil, fmt.Errorf("MFA_ENCRYPTION_KEY: %w", err)
+		}
+		config.Webhook.EncryptionKey = key
+	}
+	if config.MagicLink.SigningKey == "" {
+		key, err := deriveEncryptionKey(config.MFA.EncryptionKey, "magic-links")
+		if err != nil {
+			return nil, fmt.Errorf("MFA_ENCRYPTION_KEY: %w", err)
+		}
+		config.MagicLink.SigningKey = key
+	}
+	if config.Webhook.MaxAttempts <= 0 || config.Webhook.RetryBase <= 0 || config.Webhook.RetryMax < config.Webhook.RetryBase {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS and WEBHOOK_RETRY_BASE must be positive and WEBHOOK_RETRY_MAX not lower than the base delay")
+	}
+	if config.Webhook.Timeout <= 0 || config.Webhook.PollInterval <= 0 || config.Webhook.BatchSize <= 0 {
+		return nil, fmt.Errorf("WEBHOOK_TIMEOUT, WEBHOOK_POLL_INTERVAL and WEBHOOK_BATCH_SIZE must be positive")
+	}
+	for _, proxy := range config.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: must be an IP address or CIDR range", proxy)
+			}
+		}
+	}
+	switch config.RateLimit.Backend {
+	case "memory", "postgres", "none":
+	default:
+		return nil, fmt.Errorf("unsupported RATE_LIMIT_BACKEND %q", config.RateLimit.Backend)
+	}
+	if config.RateLimit.Period <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_PERIOD must be positive")
+	}
+	switch config.Mail.Driver {
+	case "log":
+	case "smtp":
+		if config.Mail.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAIL_DRIVER is smtp")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported MAIL_DRIVER %q", config.Mail.Driver)
+	}
+	if len(config.OAuth.Google.Issuers) == 0 {
+		config.OAuth.Google.Issuers = []string{"https://accounts.google.com", "accounts.google.com"}
+	}
+	if config.JWT.AccessTokenAudience == config.JWT.RefreshTokenAudience {
+		return nil, fmt.Errorf("JWT_ACCESS_TOKEN_AUDIENCE and JWT_REFRESH_TOKEN_AUDIENCE must be different")
 	}
 
 	return config, nil
 }
 
+// deriveEncryptionKey deriva con HKDF-SHA256 una clave AES-256 propia de cada uso a partir de la clave maestra,
+// de modo que comprometer el cifrado de un tipo de secreto no expone los demás
+func deriveEncryptionKey(encodedMasterKey, purpose string) (string, error) {
+	master, err := base64.StdEncoding.DecodeString(encodedMasterKey)
+	if err != nil || len(master) != 32 {
+		return "", fmt.Errorf("must be a base64 encoded 32-byte key")
+	}
+
+	key, err := hkdf.Key(sha256.New, master, nil, "bikes2road/"+purpose, 32)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive %s key: %w", purpose, err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
 // getEnv obtiene una variable de entorno o retorna un valor por defecto
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -85,6 +544,47 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getListEnv obtiene una lista separada por comas desde una variable de entorno
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getBoolEnv obtiene un booleano desde una variable de entorno
+func getBoolEnv(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getMapEnv obtiene pares "clave:valor" separados por comas desde una variable de entorno
+func getMapEnv(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range getListEnv(key) {
+		k, v, found := strings.Cut(pair, ":")
+		if found && k != "" && v != "" {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+// getIntEnv obtiene un entero desde una variable de entorno
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 // getDurationEnv obtiene una duración desde una variable de entorno (en horas)
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
@@ -99,3 +599,23 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 
 	return time.Duration(hours) * time.Hour
 }
+
+// getMinutesEnv obtiene una duración desde una variable de entorno (en minutos)
+func getMinutesEnv(key string, defaultValue time.Duration) time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// getSecondsEnv obtiene una duración desde una variable de entorno (en segundos)
+func getSecondsEnv(key string, defaultValue time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+
+	return time.Duration(seconds) * time.Second
+}
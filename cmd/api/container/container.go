@@ -1,26 +1,52 @@
 package container
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/bikes2road/authentication/cmd/api/config"
+	"github.com/bikes2road/authentication/internal/adapters/audit"
 	httpAdapter "github.com/bikes2road/authentication/internal/adapters/http"
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/adapters/mailer"
+	"github.com/bikes2road/authentication/internal/adapters/oidc"
 	"github.com/bikes2road/authentication/internal/adapters/postgres"
+	"github.com/bikes2road/authentication/internal/adapters/ratelimit"
+	"github.com/bikes2road/authentication/internal/adapters/sms"
+	"github.com/bikes2road/authentication/internal/adapters/webhook"
+	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
 	"github.com/bikes2road/authentication/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Container contiene todas las dependencias de la aplicación
 type Container struct {
-	Config        *config.Config
-	AuthHandler   ports.AuthHandler
-	HealthHandler ports.HealthHandler
-	Router        *gin.Engine
+	Config           *config.Config
+	AuthHandler      ports.AuthHandler
+	HealthHandler    ports.HealthHandler
+	DiscoveryHandler ports.DiscoveryHandler
+	AdminHandler     ports.AdminHandler
+	IdentityHandler  ports.IdentityHandler
+	EmailHandler     ports.EmailHandler
+	PasswordHandler  ports.PasswordHandler
+	MFAHandler       ports.MFAHandler
+	PasskeyHandler   ports.PasskeyHandler
+	SessionHandler   ports.SessionHandler
+	WebhookHandler   ports.WebhookHandler
+	Router           *gin.Engine
+
+	pool        *pgxpool.Pool
+	auditLogger ports.BufferedAuditLogger
+	// stopBackground detiene las tareas en segundo plano (limpiezas, keyring, webhooks)
+	stopBackground context.CancelFunc
 }
 
 // New crea un nuevo container con todas las dependencias inyectadas
-func New(cfg *config.Config) (*Container, error) {
+func New(cfg *config.Config) (_ *Container, err error) {
 	pool, err := postgres.NewClient(postgres.ClientConfig{
 		Host:     cfg.Postgres.Host,
 		Port:     cfg.Postgres.Port,
@@ -38,27 +64,325 @@ func New(cfg *config.Config) (*Container, error) {
 	}
 
 	userRepository := postgres.NewUserRepository(pool)
-	userService := services.NewUserService(userRepository)
+	refreshTokenRepository := postgres.NewRefreshTokenRepository(pool)
+	tokenRevocationRepository := postgres.NewTokenRevocationRepository(pool)
+	sessionRepository := postgres.NewSessionRepository(pool)
+	userIdentityRepository := postgres.NewUserIdentityRepository(pool)
+	oneTimeTokenRepository := postgres.NewOneTimeTokenRepository(pool)
+	loginAttemptRepository := postgres.NewLoginAttemptRepository(pool)
+	mfaRepository := postgres.NewMFARepository(pool)
+	passkeyRepository := postgres.NewPasskeyRepository(pool)
+	webAuthnSessionRepository := postgres.NewWebAuthnSessionRepository(pool)
+	phoneOTPRepository := postgres.NewPhoneOTPRepository(pool)
+	authEventRepository := postgres.NewAuthEventRepository(pool)
+	webhookRepository := postgres.NewWebhookRepository(pool)
+	signingKeyRepository := postgres.NewSigningKeyRepository(pool)
+	transactor := postgres.NewTransactor(pool)
+	// Las tareas en segundo plano viven hasta que se cierra el container
+	background, stopBackground := context.WithCancel(context.Background())
+	defer func() {
+		if err != nil {
+			stopBackground()
+		}
+	}()
+
+	// Los eventos se persisten en segundo plano; los que no se pueden escribir acaban en el log
+	auditLogger := audit.NewBufferedLogger(background, authEventRepository, audit.NewLogAuditLogger(), audit.BufferedLoggerConfig{
+		BufferSize:    cfg.Audit.BufferSize,
+		BatchSize:     cfg.Audit.BatchSize,
+		FlushInterval: cfg.Audit.FlushInterval,
+	})
+	passwordHasher, err := services.NewPasswordHasher(services.PasswordHasherConfig{
+		Algorithm:         cfg.Password.HashAlgorithm,
+		BcryptCost:        cfg.Password.BcryptCost,
+		Argon2Memory:      uint32(cfg.Password.Argon2Memory),
+		Argon2Time:        uint32(cfg.Password.Argon2Time),
+		Argon2Parallelism: uint8(cfg.Password.Argon2Parallelism),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password hasher: %w", err)
+	}
+	lockoutService := services.NewLockoutService(loginAttemptRepository, auditLogger, services.LockoutConfig{
+		Threshold:  cfg.Lockout.Threshold,
+		BaseDelay:  cfg.Lockout.BaseDelay,
+		MaxDelay:   cfg.Lockout.MaxDelay,
+		ResetAfter: cfg.Lockout.ResetAfter,
+	})
+	userService := services.NewUserService(userRepository, passwordHasher, lockoutService)
+	webhookService, err := services.NewWebhookService(webhookRepository, webhook.NewHTTPSender(cfg.Webhook.Timeout), services.WebhookServiceConfig{
+		EncryptionKey: cfg.Webhook.EncryptionKey,
+		MaxAttempts:   cfg.Webhook.MaxAttempts,
+		RetryBase:     cfg.Webhook.RetryBase,
+		RetryMax:      cfg.Webhook.RetryMax,
+		// Una pasada envía las entregas una a una: el lease cubre el peor caso de todas agotando el timeout
+		Lease:             time.Duration(cfg.Webhook.BatchSize)*cfg.Webhook.Timeout + cfg.Webhook.Timeout,
+		BatchSize:         cfg.Webhook.BatchSize,
+		AllowInsecureURLs: cfg.Webhook.AllowInsecureURLs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook service: %w", err)
+	}
 
 	// Crear servicios
-	jwtService := services.NewJWTService(
-		cfg.JWT.SecretKey,
-		cfg.JWT.AccessTokenExpiration,
-		cfg.JWT.RefreshTokenExpiration,
+	var privateKeyPEM []byte
+	if cfg.JWT.PrivateKeyPath != "" {
+		privateKeyPEM, err = os.ReadFile(cfg.JWT.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+	}
+
+	retiredPrivateKeyPEMs := make([][]byte, 0, len(cfg.JWT.RetiredPrivateKeyPaths))
+	for _, path := range cfg.JWT.RetiredPrivateKeyPaths {
+		retiredPEM, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read retired JWT private key: %w", err)
+		}
+		retiredPrivateKeyPEMs = append(retiredPrivateKeyPEMs, retiredPEM)
+	}
+
+	keyring, err := services.NewKeyring(context.Background(), signingKeyRepository, services.KeyringConfig{
+		Algorithm:             cfg.JWT.SigningAlgorithm,
+		SecretKey:             cfg.JWT.SecretKey,
+		PrivateKeyPEM:         privateKeyPEM,
+		RetiredSecretKeys:     cfg.JWT.RetiredSecretKeys,
+		RetiredPrivateKeyPEMs: retiredPrivateKeyPEMs,
+		GracePeriod:           cfg.JWT.KeyGracePeriod,
+		EncryptionKey:         cfg.JWT.KeyEncryptionKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize keyring: %w", err)
+	}
+
+	jwtService := services.NewJWTService(keyring, services.JWTServiceConfig{
+		Issuer:                 cfg.JWT.Issuer,
+		ClientID:               cfg.JWT.ClientID,
+		Scope:                  cfg.JWT.Scope,
+		AccessTokenExpiration:  cfg.JWT.AccessTokenExpiration,
+		RefreshTokenExpiration: cfg.JWT.RefreshTokenExpiration,
+		AccessTokenAudience:    cfg.JWT.AccessTokenAudience,
+		RefreshTokenAudience:   cfg.JWT.RefreshTokenAudience,
+		MFAChallengeExpiration: cfg.MFA.ChallengeTTL,
+	})
+	identityVerifier := oidc.NewIdentityVerifier(oidc.Config{
+		Providers: []oidc.ProviderConfig{
+			{
+				Name:      domain.ProviderGoogle,
+				JWKSURL:   cfg.OAuth.Google.JWKSURL,
+				Issuers:   cfg.OAuth.Google.Issuers,
+				ClientIDs: cfg.OAuth.Google.ClientIDs,
+			},
+		},
+	})
+	identityService := services.NewIdentityService(identityVerifier, userIdentityRepository, userService, auditLogger, services.ProvisioningConfig{
+		DefaultRole: cfg.OAuth.DefaultRole,
+		Providers: map[string]services.ProviderProvisioning{
+			domain.ProviderGoogle: {
+				Enabled:             cfg.OAuth.Google.AutoProvision,
+				AllowedEmailDomains: cfg.OAuth.Google.AllowedEmailDomains,
+			},
+		},
+	})
+	var mailSender ports.Mailer
+	if cfg.Mail.Driver == "smtp" {
+		mailSender = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			Username: cfg.Mail.SMTPUsername,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+		})
+	} else {
+		mailSender = mailer.NewLogMailer(cfg.Mail.LogDir)
+	}
+	emailVerificationService := services.NewEmailVerificationService(userService, oneTimeTokenRepository, mailSender, services.EmailVerificationConfig{
+		VerificationURL: cfg.Registration.EmailVerificationURL,
+		TokenTTL:        cfg.Registration.EmailVerificationTTL,
+	})
+	passwordService := services.NewPasswordService(
+		userService,
+		oneTimeTokenRepository,
+		refreshTokenRepository,
+		tokenRevocationRepository,
+		sessionRepository,
+		mailSender,
+		auditLogger,
+		webhookService,
+		transactor,
+		services.PasswordServiceConfig{
+			ResetURL:      cfg.Password.ResetURL,
+			ResetTokenTTL: cfg.Password.ResetTokenTTL,
+		},
 	)
-	authService := services.NewAuthService(jwtService, userService)
+	passkeyService, err := services.NewPasskeyService(userService, passkeyRepository, webAuthnSessionRepository, auditLogger, services.PasskeyServiceConfig{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+		Attestation:   cfg.WebAuthn.Attestation,
+		SessionTTL:    cfg.WebAuthn.SessionTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create passkey service: %w", err)
+	}
+	mfaService, err := services.NewMFAService(userService, mfaRepository, passkeyRepository, lockoutService, auditLogger, services.MFAServiceConfig{
+		Issuer:        cfg.MFA.Issuer,
+		EncryptionKey: cfg.MFA.EncryptionKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mfa service: %w", err)
+	}
+	var rateLimiter ports.RateLimiter
+	switch cfg.RateLimit.Backend {
+	case "postgres":
+		rateLimiter = postgres.NewRateLimiter(pool)
+	case "memory":
+		rateLimiter = ratelimit.NewMemoryRateLimiter()
+	}
+	if rateLimiter != nil {
+		go services.RunRateLimitCleanup(background, rateLimiter, cfg.RateLimit.Period)
+	}
+	// Los límites por email y por teléfono evitan usar el servicio para inundar a
+	// terceros de enlaces y SMS, así que se aplican también con RATE_LIMIT_BACKEND=none
+	deliveryLimiter := rateLimiter
+	if deliveryLimiter == nil {
+		deliveryLimiter = ratelimit.NewMemoryRateLimiter()
+		go services.RunRateLimitCleanup(background, deliveryLimiter, cfg.RateLimit.Period)
+	}
+
+	magicLinkService, err := services.NewMagicLinkService(userService, oneTimeTokenRepository, mailSender, deliveryLimiter, services.MagicLinkServiceConfig{
+		URL:        cfg.MagicLink.URL,
+		TokenTTL:   cfg.MagicLink.TTL,
+		EmailLimit: domain.RateLimit{Burst: cfg.MagicLink.PerEmail, Period: cfg.MagicLink.TTL},
+		SigningKey: cfg.MagicLink.SigningKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create magic link service: %w", err)
+	}
+	var smsSender ports.SMSSender
+	if cfg.SMS.Driver == "webhook" {
+		smsSender = sms.NewWebhookSender(sms.WebhookConfig{
+			URL:     cfg.SMS.WebhookURL,
+			Token:   cfg.SMS.WebhookToken,
+			Timeout: 10 * time.Second,
+		})
+	} else {
+		smsSender = sms.NewLogSender()
+	}
+	smsLoginService := services.NewSMSLoginService(userService, phoneOTPRepository, smsSender, deliveryLimiter, services.SMSLoginServiceConfig{
+		CodeTTL:     cfg.SMS.CodeTTL,
+		MaxAttempts: cfg.SMS.MaxAttempts,
+		PhoneLimit:  domain.RateLimit{Burst: cfg.SMS.PerPhone, Period: cfg.SMS.CodeTTL},
+	})
+	authService := services.NewAuditedAuthService(services.NewAuthService(
+		jwtService,
+		userService,
+		refreshTokenRepository,
+		tokenRevocationRepository,
+		sessionRepository,
+		auditLogger,
+		webhookService,
+		transactor,
+		identityService,
+		emailVerificationService,
+		mfaService,
+		passkeyService,
+		magicLinkService,
+		smsLoginService,
+		lockoutService,
+		services.AuthServiceConfig{
+			IssueTokensOnRegister: cfg.Registration.IssueTokens,
+			RequireVerifiedEmail:  cfg.Registration.RequireVerifiedEmail,
+			RecentAuthMaxAge:      cfg.Password.RecentAuthMaxAge,
+		},
+	), jwtService, auditLogger)
+	auditService := services.NewAuditService(authEventRepository)
+	sessionService := services.NewSessionService(sessionRepository, refreshTokenRepository, auditLogger, webhookService, transactor)
+	accountService := services.NewAccountService(userService, refreshTokenRepository, tokenRevocationRepository, sessionRepository, auditLogger, webhookService, transactor)
+
+	// Limpieza en segundo plano de revocaciones expiradas
+	go services.RunRevocationCleanup(background, tokenRevocationRepository, cfg.JWT.RevocationCleanupInterval)
+	go services.RunOneTimeTokenCleanup(background, oneTimeTokenRepository, cfg.JWT.RevocationCleanupInterval)
+	go services.RunWebAuthnSessionCleanup(background, webAuthnSessionRepository, cfg.JWT.RevocationCleanupInterval)
+	go services.RunPhoneOTPCleanup(background, phoneOTPRepository, cfg.JWT.RevocationCleanupInterval)
+	go services.RunSessionCleanup(background, sessionRepository, cfg.JWT.RevocationCleanupInterval)
+	go services.RunSigningKeyCleanup(background, signingKeyRepository, cfg.JWT.RevocationCleanupInterval)
+
+	// Las rotaciones hechas en otras réplicas se adoptan en la siguiente recarga
+	go services.RunKeyringRefresh(background, keyring, cfg.JWT.KeyRefreshInterval)
+
+	// Entrega en segundo plano de los webhooks pendientes del outbox
+	go services.RunWebhookDispatcher(background, webhookService, cfg.Webhook.PollInterval)
+
+	resourceServerRegistry := services.NewResourceServerRegistry(cfg.Introspection.Clients)
 
 	// Crear handlers
 	authHandler := httpAdapter.NewAuthHandler(authService)
 	healthHandler := httpAdapter.NewHealthHandler()
+	discoveryHandler := httpAdapter.NewDiscoveryHandler(jwtService, cfg.Server.PublicURL)
+	adminHandler := httpAdapter.NewAdminHandler(keyring, userService, auditService, accountService)
+	identityHandler := httpAdapter.NewIdentityHandler(identityService)
+	emailHandler := httpAdapter.NewEmailHandler(emailVerificationService)
+	passwordHandler := httpAdapter.NewPasswordHandler(passwordService)
+	mfaHandler := httpAdapter.NewMFAHandler(mfaService)
+	passkeyHandler := httpAdapter.NewPasskeyHandler(passkeyService)
+	sessionHandler := httpAdapter.NewSessionHandler(sessionService)
+	webhookHandler := httpAdapter.NewWebhookHandler(webhookService)
 
 	// Configurar router
-	router := httpAdapter.SetupRouter(authHandler, healthHandler)
+	router, err := httpAdapter.SetupRouter(
+		authHandler,
+		healthHandler,
+		discoveryHandler,
+		adminHandler,
+		identityHandler,
+		emailHandler,
+		passwordHandler,
+		mfaHandler,
+		passkeyHandler,
+		sessionHandler,
+		webhookHandler,
+		authService,
+		resourceServerRegistry,
+		rateLimiter,
+		middleware.RateLimitConfig{
+			PerIP:      domain.RateLimit{Burst: cfg.RateLimit.PerIP, Period: cfg.RateLimit.Period},
+			PerAccount: domain.RateLimit{Burst: cfg.RateLimit.PerAccount, Period: cfg.RateLimit.Period},
+			PerRoute:   domain.RateLimit{Burst: cfg.RateLimit.PerRoute, Period: cfg.RateLimit.Period},
+		},
+		cfg.Server.TrustedProxies,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure trusted proxies: %w", err)
+	}
 
 	return &Container{
-		Config:        cfg,
-		AuthHandler:   authHandler,
-		HealthHandler: healthHandler,
-		Router:        router,
+		Config:           cfg,
+		AuthHandler:      authHandler,
+		HealthHandler:    healthHandler,
+		DiscoveryHandler: discoveryHandler,
+		AdminHandler:     adminHandler,
+		IdentityHandler:  identityHandler,
+		EmailHandler:     emailHandler,
+		PasswordHandler:  passwordHandler,
+		MFAHandler:       mfaHandler,
+		PasskeyHandler:   passkeyHandler,
+		SessionHandler:   sessionHandler,
+		WebhookHandler:   webhookHandler,
+		Router:           router,
+		pool:             pool,
+		auditLogger:      auditLogger,
+		stopBackground:   stopBackground,
 	}, nil
 }
+
+// Close detiene las tareas en segundo plano, persiste los eventos de auditoría pendientes
+// y cierra el pool de PostgreSQL. Debe llamarse tras dejar de atender peticiones.
+func (c *Container) Close(ctx context.Context) error {
+	c.stopBackground()
+	err := c.auditLogger.Close(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to flush audit events: %w", err)
+	}
+	c.pool.Close()
+	return err
+}
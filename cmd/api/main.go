@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/bikes2road/authentication/cmd/api/config"
 	"github.com/bikes2road/authentication/cmd/api/container"
@@ -28,6 +35,12 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.basic BasicAuth
+// @description Credenciales client_id / client_secret de un resource server registrado.
+
+// shutdownTimeout limita lo que se espera a las peticiones en curso y a la escritura de la auditoría pendiente
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	// Cargar configuración
 	cfg, err := config.Load()
@@ -46,7 +59,26 @@ func main() {
 	log.Printf("Starting authentication service on %s", addr)
 	log.Printf("Swagger documentation available at http://%s/api/auth/v1/swagger/index.html", addr)
 
-	if err := c.Router.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{Addr: addr, Handler: c.Router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Apagado ordenado: dejar de aceptar peticiones, terminar las que están en curso y
+	// después vaciar la auditoría pendiente antes de cerrar el pool de PostgreSQL
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down authentication service...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server gracefully: %v", err)
+	}
+	if err := c.Close(shutdownCtx); err != nil {
+		log.Printf("Failed to close container: %v", err)
 	}
 }
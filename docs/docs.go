@@ -23,65 +23,162 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/.well-known/jwks.json": {
             "get": {
-                "description": "Verifica que el servicio esté funcionando",
+                "description": "Retorna el JSON Web Key Set con las claves públicas para verificar los tokens emitidos. Vacío cuando se firma con HS256",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "discovery"
                 ],
-                "summary": "Health check",
+                "summary": "Claves públicas de verificación",
                 "responses": {
                     "200": {
-                        "description": "Servicio funcionando correctamente",
+                        "description": "Claves públicas",
                         "schema": {
-                            "$ref": "#/definitions/internal_adapters_http.HealthResponse"
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.JWKS"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Autentica un usuario con email y password, retorna tokens JWT",
-                "consumes": [
+        "/.well-known/openid-configuration": {
+            "get": {
+                "description": "Describe el emisor, el JWKS, los endpoints de userinfo e introspección, los endpoints propios de login, refresh y logout y los scopes y claims soportados",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "discovery"
+                ],
+                "summary": "Documento de descubrimiento OpenID Connect",
+                "responses": {
+                    "200": {
+                        "description": "Metadatos OpenID Connect",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.OpenIDConfiguration"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/audit/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista los eventos de autenticación del más reciente al más antiguo. Todos los filtros son opcionales y se combinan; para la página siguiente se envía next_cursor como cursor con los mismos filtros",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Login de usuario",
+                "summary": "Consultar el registro de auditoría",
                 "parameters": [
                     {
-                        "description": "Credenciales de login",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginRequest"
-                        }
+                        "type": "string",
+                        "example": "login",
+                        "description": "Tipo de evento",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email, nick, teléfono o proveedor con el que se intentó la operación",
+                        "name": "identifier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IP del cliente",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failure",
+                            "mfa_required"
+                        ],
+                        "type": "string",
+                        "description": "Resultado",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Request-ID de la petición",
+                        "name": "correlation_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (RFC 3339, incluido)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (RFC 3339, excluido)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor de la página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Eventos por página (1-200, 50 por defecto)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login exitoso",
+                        "description": "Página de eventos",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.AuthEventsResponse"
                         }
                     },
                     "400": {
-                        "description": "Request inválido",
+                        "description": "Parámetros mal formados",
                         "schema": {
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Credenciales inválidas",
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Cursor, límite o rango de fechas inválidos",
                         "schema": {
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
@@ -95,45 +192,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/login": {
-            "post": {
-                "description": "Autentica un usuario con Google OAuth y retorna tokens JWT",
-                "consumes": [
-                    "application/json"
+        "/admin/keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lista la clave activa y las claves retiradas que todavía verifican tokens. No incluye material criptográfico",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "OAuth login de usuario",
-                "parameters": [
-                    {
-                        "description": "Credenciales de OAuth",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Listar claves de firma",
+                "responses": {
+                    "200": {
+                        "description": "Claves de firma",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.SigningKeysResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_ports.UserInfoOAuth"
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/keys/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Genera una nueva clave activa. La anterior deja de firmar pero verifica tokens hasta el fin del periodo de gracia",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
                 ],
+                "summary": "Rotar la clave de firma",
                 "responses": {
                     "200": {
-                        "description": "Login exitoso",
+                        "description": "Nueva clave activa",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SigningKey"
                         }
                     },
-                    "400": {
-                        "description": "Request inválido",
+                    "401": {
+                        "description": "Token inválido o expirado",
                         "schema": {
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Credenciales inválidas",
+                    "403": {
+                        "description": "Permisos insuficientes",
                         "schema": {
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
@@ -147,42 +272,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/refresh": {
+        "/admin/users/{id}/deactivate": {
             "post": {
-                "description": "Genera un nuevo par de tokens usando un refresh token válido",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Impide que la cuenta vuelva a iniciar sesión, cierra todas sus sesiones y revoca sus tokens. Notifica los webhooks user.deactivated y user.sessions.revoked. Desactivar una cuenta ya inactiva no tiene efecto",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Refrescar token JWT",
+                "summary": "Desactivar una cuenta",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.RefreshRequest"
-                        }
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Tokens refrescados",
+                        "description": "Cuenta desactivada",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.RefreshResponse"
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Request inválido",
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
                         "schema": {
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Levanta el bloqueo por intentos de login fallidos y reinicia el contador de fallos de la cuenta",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Desbloquear una cuenta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID del usuario",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cuenta desbloqueada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
                     },
                     "401": {
                         "description": "Token inválido o expirado",
@@ -190,6 +367,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -199,9 +388,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/validate": {
+        "/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista las suscripciones registradas, sin sus secretos de firma",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Listar suscripciones de webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Suscripciones",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebhookSubscriptionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Valida un token JWT y retorna sus claims si es válido",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registra un endpoint que recibirá los eventos de seguridad indicados. La respuesta incluye el secreto de firma, que no se vuelve a mostrar",
                 "consumes": [
                     "application/json"
                 ],
@@ -209,25 +444,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "webhooks"
                 ],
-                "summary": "Validar token JWT",
+                "summary": "Crear una suscripción de webhooks",
                 "parameters": [
                     {
-                        "description": "Token a validar",
+                        "description": "Endpoint y eventos",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ValidateRequest"
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.CreateWebhookRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Token validado",
+                    "201": {
+                        "description": "Suscripción creada",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ValidateResponse"
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebhookSubscriptionCreated"
                         }
                     },
                     "400": {
@@ -236,6 +471,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "URL o tipos de evento inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Error interno del servidor",
                         "schema": {
@@ -244,23 +497,2778 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_bikes2road_authentication_internal_domain.JWTClaims": {
-            "type": "object",
-            "properties": {
-                "aud": {
-                    "description": "the ` + "`" + `aud` + "`" + ` (Audience) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.3",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        },
+        "/admin/webhooks/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "email": {
-                    "type": "string"
-                },
-                "exp": {
+                ],
+                "description": "Lista las entregas más recientes del outbox con su estado, intentos y último error. Todos los filtros son opcionales",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Listar entregas de webhooks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Estado de la entrega",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la suscripción",
+                        "name": "subscription_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número de entregas (1-200, 50 por defecto)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entregas",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros mal formados",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Filtros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/deliveries/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Vuelve a poner en cola la entrega con los intentos a cero, normalmente una que agotó sus reintentos (dead). Se reenvía el mismo evento con el mismo X-Webhook-Id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Reenviar una entrega de webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la entrega",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Entrega en cola",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entrega no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina la suscripción y descarta sus entregas, incluidas las pendientes",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Eliminar una suscripción de webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la suscripción",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Suscripción eliminada"
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Suscripción no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/resend": {
+            "post": {
+                "description": "Envía un nuevo enlace de verificación. La respuesta es siempre la misma exista o no la cuenta",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "summary": "Reenviar email de verificación",
+                "parameters": [
+                    {
+                        "description": "Email de la cuenta",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ResendVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Solicitud aceptada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/verify": {
+            "get": {
+                "description": "Consume el token de un solo uso del enlace de verificación y marca el email como verificado. Acepta el token como query (GET) o en el body (POST)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "summary": "Verificar email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token de verificación (GET)",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Token de verificación (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.EmailVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email verificado",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token inválido, expirado o ya usado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Consume el token de un solo uso del enlace de verificación y marca el email como verificado. Acepta el token como query (GET) o en el body (POST)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "summary": "Verificar email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token de verificación (GET)",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Token de verificación (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.EmailVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email verificado",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token inválido, expirado o ya usado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Verifica que el servicio esté funcionando",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Servicio funcionando correctamente",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista las identidades de proveedores externos vinculadas a la cuenta autenticada",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Listar proveedores vinculados",
+                "responses": {
+                    "200": {
+                        "description": "Identidades vinculadas",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.UserIdentitiesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifica el ID token del proveedor y lo vincula a la cuenta autenticada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Vincular un proveedor",
+                "parameters": [
+                    {
+                        "description": "ID token del proveedor",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LinkIdentityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Identidad vinculada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.UserIdentity"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido o proveedor no soportado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token o ID token inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "La identidad o el proveedor ya están vinculados",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{provider}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Desvincula el proveedor de la cuenta autenticada. Se rechaza si la cuenta quedaría sin contraseña y sin proveedores",
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Desvincular un proveedor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "google",
+                        "description": "Proveedor",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Proveedor desvinculado"
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "El proveedor no está vinculado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Es el último método de inicio de sesión",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/introspect": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Informa si un access o refresh token está activo y retorna sus metadatos. Requiere credenciales de un resource server registrado",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Introspección de token (RFC 7662)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token a inspeccionar",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "access_token o refresh_token",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estado del token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.IntrospectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Credenciales de cliente inválidas",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Autentica un usuario con email y password, retorna tokens JWT. Si el usuario tiene un segundo factor, retorna mfa_required y un challenge a completar en /login/mfa",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login de usuario",
+                "parameters": [
+                    {
+                        "description": "Credenciales de login",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Credenciales inválidas",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Cuenta bloqueada temporalmente por intentos fallidos, solo con la contraseña correcta (ver cabecera Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/magic-link": {
+            "post": {
+                "description": "Envía un enlace de un solo uso para iniciar sesión sin contraseña y fija la cookie magic_link_nonce que liga el enlace a este navegador. La respuesta es siempre la misma exista o no la cuenta",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Solicitar enlace de login",
+                "parameters": [
+                    {
+                        "description": "Email de la cuenta",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MagicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Solicitud aceptada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/magic-link/verify": {
+            "get": {
+                "description": "Consume el enlace de login junto con la cookie magic_link_nonce y retorna tokens JWT con amr=[\"email\"]. Si el usuario tiene un segundo factor, retorna mfa_required. Acepta el token como query (GET) o en el body (POST)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login con enlace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token del enlace (GET)",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Token del enlace (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MagicLinkVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token inválido, expirado, ya usado o abierto en otro navegador",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario inactivo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Consume el enlace de login junto con la cookie magic_link_nonce y retorna tokens JWT con amr=[\"email\"]. Si el usuario tiene un segundo factor, retorna mfa_required. Acepta el token como query (GET) o en el body (POST)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login con enlace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token del enlace (GET)",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Token del enlace (POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MagicLinkVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token inválido, expirado, ya usado o abierto en otro navegador",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario inactivo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/mfa": {
+            "post": {
+                "description": "Completa el login con el challenge devuelto por /login y un código TOTP o de recuperación, o la aserción de una passkey iniciada en /login/mfa/webauthn. Retorna tokens JWT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Segundo factor del login",
+                "parameters": [
+                    {
+                        "description": "Challenge y código o passkey",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFALoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido, ceremonia WebAuthn expirada o factor ya usado como primer factor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Challenge, código o passkey inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/mfa/webauthn": {
+            "post": {
+                "description": "Retorna las opciones para navigator.credentials.get() limitadas a las passkeys del usuario del challenge. La aserción se envía a /login/mfa junto con session_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Iniciar el segundo factor con passkey",
+                "parameters": [
+                    {
+                        "description": "Challenge del login",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFAPasskeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Opciones de la ceremonia",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebAuthnOptionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido o el primer factor ya fue una passkey",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Challenge inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "El usuario no tiene passkeys",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/passkey/begin": {
+            "post": {
+                "description": "Retorna las opciones para navigator.credentials.get() de un login sin contraseña; el autenticador ofrece las passkeys guardadas para este dominio",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Iniciar login con passkey",
+                "responses": {
+                    "200": {
+                        "description": "Opciones de la ceremonia",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebAuthnOptionsResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/passkey/finish": {
+            "post": {
+                "description": "Verifica la aserción de la passkey y retorna tokens JWT. Si el autenticador no verificó al usuario (PIN o biometría) y tiene otro factor, retorna mfa_required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Completar login con passkey",
+                "parameters": [
+                    {
+                        "description": "Sesión y aserción del autenticador",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasskeyAssertionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido o ceremonia expirada",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Passkey inválida o usuario inactivo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/sms": {
+            "post": {
+                "description": "Envía un código de 6 dígitos al teléfono (formato internacional, se normaliza a E.164) si pertenece a una cuenta activa. La respuesta es siempre la misma exista o no la cuenta",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Solicitar código de login por SMS",
+                "parameters": [
+                    {
+                        "description": "Teléfono de la cuenta",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SMSLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Solicitud aceptada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "El teléfono no está en formato internacional",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/sms/verify": {
+            "post": {
+                "description": "Verifica el código recibido por SMS y retorna tokens JWT con amr=[\"sms\"]. Si el usuario tiene un segundo factor, retorna mfa_required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login con código SMS",
+                "parameters": [
+                    {
+                        "description": "Teléfono y código",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SMSLoginVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Código inválido, expirado o sin intentos, o usuario inactivo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Cuenta bloqueada temporalmente (ver cabecera Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoca el access token enviado y, si se incluye, el refresh token de la misma sesión",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout de la sesión actual",
+                "parameters": [
+                    {
+                        "description": "Refresh token de la sesión",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Sesión cerrada"
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout/all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoca todos los refresh tokens del usuario y todos los tokens emitidos hasta el momento",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout de todas las sesiones",
+                "responses": {
+                    "204": {
+                        "description": "Sesiones cerradas"
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Indica si la cuenta autenticada tiene MFA activo y cuántos códigos de recuperación le quedan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Estado del segundo factor",
+                "responses": {
+                    "200": {
+                        "description": "Estado del segundo factor",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFAStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Desactiva el segundo factor tras comprobar un código TOTP o de recuperación",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Desactivar MFA",
+                "parameters": [
+                    {
+                        "description": "Código TOTP o de recuperación",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "MFA desactivado"
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "MFA no está activo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Código incorrecto o ya usado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invalida los códigos de recuperación anteriores y genera diez nuevos tras comprobar un código TOTP o de recuperación",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Regenerar códigos de recuperación",
+                "parameters": [
+                    {
+                        "description": "Código TOTP o de recuperación",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Nuevos códigos de recuperación",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFARecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "MFA no está activo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Código incorrecto o ya usado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/totp/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Activa MFA con el primer código generado por la app y devuelve diez códigos de recuperación de un solo uso, que solo se muestran esta vez",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Confirmar el registro TOTP",
+                "parameters": [
+                    {
+                        "description": "Código TOTP",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "MFA activado",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFARecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No hay un registro pendiente o MFA ya está activo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Código incorrecto",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/totp/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Genera un secreto TOTP y lo devuelve como otpauth URI y código QR (PNG). No se activa hasta confirmarlo con un primer código",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Iniciar el registro TOTP",
+                "responses": {
+                    "200": {
+                        "description": "Secreto pendiente de confirmar",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFAEnrollmentResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "MFA ya está activo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/login": {
+            "post": {
+                "description": "Verifica el ID token emitido por el proveedor (Google) y retorna tokens JWT del usuario asociado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "OAuth login de usuario",
+                "parameters": [
+                    {
+                        "description": "ID token del proveedor",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_ports.OAuthLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login exitoso",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido o proveedor no soportado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "ID token inválido, email no verificado o usuario inexistente",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Existe una cuenta con ese email sin verificar; hay que iniciar sesión en ella y vincular la identidad",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Un alta concurrente tomó el nick_name derivado (fields.nick_name); se puede reintentar",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista las passkeys registradas en la cuenta autenticada",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkeys"
+                ],
+                "summary": "Listar passkeys",
+                "responses": {
+                    "200": {
+                        "description": "Passkeys registradas",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasskeysResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys/register/begin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna las opciones para navigator.credentials.create(). La respuesta del autenticador se envía a /passkeys/register/finish junto con session_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkeys"
+                ],
+                "summary": "Iniciar el registro de una passkey",
+                "responses": {
+                    "200": {
+                        "description": "Opciones de la ceremonia",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebAuthnOptionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys/register/finish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifica la respuesta del autenticador y guarda la passkey en la cuenta autenticada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkeys"
+                ],
+                "summary": "Completar el registro de una passkey",
+                "parameters": [
+                    {
+                        "description": "Sesión, nombre y credencial creada",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasskeyRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Passkey registrada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasskeyInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido o ceremonia expirada",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "La passkey ya está registrada",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "La credencial no se pudo verificar",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina una passkey de la cuenta autenticada",
+                "tags": [
+                    "passkeys"
+                ],
+                "summary": "Eliminar una passkey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la passkey",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Passkey eliminada"
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "La passkey no existe",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/change": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cambia la contraseña del usuario autenticado comprobando la actual. Opcionalmente cierra el resto de sesiones y devuelve tokens nuevos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "password"
+                ],
+                "summary": "Cambiar contraseña",
+                "parameters": [
+                    {
+                        "description": "Contraseña actual y nueva",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contraseña cambiada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasswordChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "La cuenta no tiene contraseña",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Contraseña actual incorrecta o nueva contraseña inválida",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "Envía un enlace de un solo uso para restablecer la contraseña. La respuesta es siempre la misma exista o no la cuenta",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "password"
+                ],
+                "summary": "Recuperar contraseña",
+                "parameters": [
+                    {
+                        "description": "Email de la cuenta",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Solicitud aceptada",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Aplica una nueva contraseña con el token del enlace de recuperación y cierra todas las sesiones del usuario",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "password"
+                ],
+                "summary": "Restablecer contraseña",
+                "parameters": [
+                    {
+                        "description": "Token y nueva contraseña",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contraseña restablecida",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token inválido, expirado o ya usado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "La contraseña no cumple la política",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/set": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Establece la primera contraseña de un usuario que solo usa OAuth. Exige haberse autenticado recientemente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "password"
+                ],
+                "summary": "Establecer contraseña",
+                "parameters": [
+                    {
+                        "description": "Nueva contraseña",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Contraseña establecida",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasswordChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o autenticación no reciente",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "La cuenta ya tiene contraseña",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "La contraseña no cumple la política",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/refresh": {
+            "post": {
+                "description": "Genera un nuevo par de tokens usando un refresh token válido",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refrescar token JWT",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens refrescados",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.RefreshResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Crea una cuenta con email y contraseña. Los errores de validación se informan por campo en \"fields\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Registro de usuario",
+                "parameters": [
+                    {
+                        "description": "Datos del nuevo usuario",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Usuario registrado",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Errores de validación por campo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista las sesiones activas de la cuenta autenticada con el dispositivo, la IP y los métodos de autenticación de cada una. La sesión del token usado se marca con current",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Listar sesiones",
+                "responses": {
+                    "200": {
+                        "description": "Sesiones activas",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cierra una sesión de la cuenta autenticada: sus access tokens dejan de ser válidos y sus refresh tokens se revocan",
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Cerrar una sesión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la sesión (claim sid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Sesión cerrada"
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "La sesión no existe o ya está cerrada",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/userinfo": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna los claims estándar de OpenID Connect del usuario del access token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Información del usuario (OpenID Connect)",
+                "responses": {
+                    "200": {
+                        "description": "Información del usuario",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.OIDCUserInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Token inválido o expirado",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/validate": {
+            "post": {
+                "description": "Valida un token JWT y retorna sus claims si es válido",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Validar token JWT",
+                "parameters": [
+                    {
+                        "description": "Token a validar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ValidateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token validado",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.ValidateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Request inválido",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/internal_adapters_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_bikes2road_authentication_internal_domain.AuthEvent": {
+            "type": "object",
+            "properties": {
+                "correlation_id": {
+                    "description": "CorrelationID es el X-Request-ID de la petición que originó el evento",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID lo asigna el almacén al persistir el evento",
+                    "type": "integer"
+                },
+                "identifier": {
+                    "description": "Identifier es el email, nick, teléfono o proveedor con el que se intentó la operación",
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "description": "Outcome está vacío en los eventos que no corresponden a una operación (p. ej. account_locked)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.AuthOutcome"
+                        }
+                    ],
+                    "example": "failure"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "invalid credentials"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.AuthEventType"
+                        }
+                    ],
+                    "example": "login"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.AuthEventType": {
+            "type": "string",
+            "enum": [
+                "login",
+                "login_oauth",
+                "login_mfa",
+                "login_mfa_passkey_begin",
+                "login_passkey_begin",
+                "login_passkey",
+                "login_magic_link_request",
+                "login_magic_link",
+                "login_sms_request",
+                "login_sms",
+                "register",
+                "token_validated",
+                "token_refreshed",
+                "token_rejected",
+                "token_introspected",
+                "logout",
+                "logout_all",
+                "userinfo",
+                "refresh_token_reuse",
+                "password_reset",
+                "password_changed",
+                "password_set",
+                "account_locked",
+                "account_unlocked",
+                "account_deactivated",
+                "mfa_enabled",
+                "mfa_disabled",
+                "mfa_recovery_codes_regenerated",
+                "mfa_recovery_code_used",
+                "session_revoked",
+                "passkey_registered",
+                "passkey_removed",
+                "passkey_clone_detected",
+                "identity_linked"
+            ],
+            "x-enum-varnames": [
+                "AuthEventLogin",
+                "AuthEventOAuthLogin",
+                "AuthEventMFALogin",
+                "AuthEventMFAPasskeyBegin",
+                "AuthEventPasskeyLoginBegin",
+                "AuthEventPasskeyLogin",
+                "AuthEventMagicLinkRequest",
+                "AuthEventMagicLinkLogin",
+                "AuthEventSMSCodeRequest",
+                "AuthEventSMSLogin",
+                "AuthEventRegister",
+                "AuthEventTokenValidated",
+                "AuthEventTokenRefreshed",
+                "AuthEventTokenRejected",
+                "AuthEventTokenIntrospected",
+                "AuthEventLogout",
+                "AuthEventLogoutAll",
+                "AuthEventUserInfo",
+                "AuthEventRefreshTokenReuse",
+                "AuthEventPasswordReset",
+                "AuthEventPasswordChanged",
+                "AuthEventPasswordSet",
+                "AuthEventAccountLocked",
+                "AuthEventAccountUnlocked",
+                "AuthEventAccountDeactivated",
+                "AuthEventMFAEnabled",
+                "AuthEventMFADisabled",
+                "AuthEventRecoveryCodesRegenerated",
+                "AuthEventRecoveryCodeUsed",
+                "AuthEventSessionRevoked",
+                "AuthEventPasskeyRegistered",
+                "AuthEventPasskeyRemoved",
+                "AuthEventPasskeyCloneDetected",
+                "AuthEventIdentityLinked"
+            ]
+        },
+        "github_com_bikes2road_authentication_internal_domain.AuthEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.AuthEvent"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor se envía como cursor para obtener la página siguiente; vacío en la última",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.AuthOutcome": {
+            "type": "string",
+            "enum": [
+                "success",
+                "failure",
+                "mfa_required"
+            ],
+            "x-enum-varnames": [
+                "AuthOutcomeSuccess",
+                "AuthOutcomeFailure",
+                "AuthOutcomeMFARequired"
+            ]
+        },
+        "github_com_bikes2road_authentication_internal_domain.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "logout_other_sessions": {
+                    "description": "LogoutOtherSessions cierra el resto de sesiones y devuelve tokens nuevos para la actual",
+                    "type": "boolean"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "N3wPass@"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SecurityEventType"
+                    },
+                    "example": [
+                        "user.password.changed",
+                        "user.deactivated"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://bookings.bikes2road.com/webhooks/auth"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.EmailVerificationRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.IntrospectionResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "aud": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "iat": {
+                    "type": "integer"
+                },
+                "iss": {
+                    "type": "string"
+                },
+                "jti": {
+                    "type": "string"
+                },
+                "nbf": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "Parámetros EC y OKP",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "Parámetros RSA",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.JWK"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.JWTClaims": {
+            "type": "object",
+            "properties": {
+                "acr": {
+                    "type": "string"
+                },
+                "amr": {
+                    "description": "AMR son los métodos con los que se autenticó el usuario (RFC 8176) y ACR el nivel resultante",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "aud": {
+                    "description": "the ` + "`" + `aud` + "`" + ` (Audience) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.3",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "auth_time": {
+                    "description": "AuthTime es el momento en que el usuario se autenticó; se conserva al refrescar los tokens",
+                    "type": "integer"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "exp": {
                     "description": "the ` + "`" + `exp` + "`" + ` (Expiration Time) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.4",
                     "allOf": [
                         {
@@ -268,90 +3276,658 @@ const docTemplate = `{
                         }
                     ]
                 },
-                "iat": {
-                    "description": "the ` + "`" + `iat` + "`" + ` (Issued At) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.6",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/jwt.NumericDate"
-                        }
+                "iat": {
+                    "description": "the ` + "`" + `iat` + "`" + ` (Issued At) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.6",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jwt.NumericDate"
+                        }
+                    ]
+                },
+                "iss": {
+                    "description": "the ` + "`" + `iss` + "`" + ` (Issuer) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.1",
+                    "type": "string"
+                },
+                "jti": {
+                    "description": "the ` + "`" + `jti` + "`" + ` (JWT ID) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.7",
+                    "type": "string"
+                },
+                "nbf": {
+                    "description": "the ` + "`" + `nbf` + "`" + ` (Not Before) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.5",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/jwt.NumericDate"
+                        }
+                    ]
+                },
+                "nick_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "sid": {
+                    "description": "SessionID identifica la sesión del servidor a la que pertenece el token",
+                    "type": "string"
+                },
+                "sub": {
+                    "description": "the ` + "`" + `sub` + "`" + ` (Subject) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.2",
+                    "type": "string"
+                },
+                "token_type": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenType"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.LinkIdentityRequest": {
+            "type": "object",
+            "required": [
+                "id_token",
+                "provider"
+            ],
+            "properties": {
+                "id_token": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "google"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email_or_nick_name",
+                "password"
+            ],
+            "properties": {
+                "email_or_nick_name": {
+                    "type": "string",
+                    "example": "[EMAIL_ADDRESS] | johndoe"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "T3st123@"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "mfa": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.MFAChallenge"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "tokens": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenPair"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.UserInfo"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFAChallenge": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "description": "ChallengeToken es de un solo uso y solo sirve para POST /login/mfa",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "Segundos hasta expiración",
+                    "type": "integer"
+                },
+                "factors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "totp",
+                        "recovery_code",
+                        "webauthn"
+                    ]
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFACodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFAEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/Bikes2Road:john@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=Bikes2Road"
+                },
+                "qr_code": {
+                    "description": "QRCode es el otpauth URI como imagen PNG en un data URI",
+                    "type": "string",
+                    "example": "data:image/png;base64,iVBORw0KGgo..."
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFALoginRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Code es un código TOTP o de recuperación",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "credential": {
+                    "type": "object"
+                },
+                "session_id": {
+                    "description": "SessionID y Credential completan el challenge con una passkey (ver POST /login/mfa/webauthn)",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFAPasskeyRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFARecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MFAStatusResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Enabled indica si TOTP está activo",
+                    "type": "boolean"
+                },
+                "passkeys": {
+                    "description": "Passkeys es el número de passkeys registradas, que también sirven como segundo factor",
+                    "type": "integer"
+                },
+                "recovery_codes_remaining": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MagicLinkRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MagicLinkVerifyRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.OIDCUserInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "family_name": {
+                    "type": "string"
+                },
+                "given_name": {
+                    "type": "string"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
+                "preferred_username": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.OpenIDConfiguration": {
+            "type": "object",
+            "properties": {
+                "acr_values_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "claims_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "introspection_endpoint": {
+                    "type": "string"
+                },
+                "introspection_endpoint_auth_methods_supported": {
+                    "description": "Métodos con los que los resource servers se autentican en el endpoint de introspección",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "jwks_uri": {
+                    "type": "string"
+                },
+                "login_endpoint": {
+                    "type": "string"
+                },
+                "logout_endpoint": {
+                    "type": "string"
+                },
+                "refresh_endpoint": {
+                    "type": "string"
+                },
+                "scopes_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userinfo_endpoint": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.PasskeyAssertionRequest": {
+            "type": "object",
+            "required": [
+                "credential",
+                "session_id"
+            ],
+            "properties": {
+                "credential": {
+                    "type": "object"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.PasskeyInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "iPhone"
+                },
+                "synced": {
+                    "type": "boolean"
+                },
+                "transports": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "internal",
+                        "hybrid"
                     ]
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.PasskeyRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "credential",
+                "session_id"
+            ],
+            "properties": {
+                "credential": {
+                    "type": "object"
                 },
-                "iss": {
-                    "description": "the ` + "`" + `iss` + "`" + ` (Issuer) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.1",
+                "name": {
+                    "description": "Name identifica la passkey en el listado; por defecto \"Passkey\"",
+                    "type": "string",
+                    "example": "iPhone"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.PasskeysResponse": {
+            "type": "object",
+            "properties": {
+                "passkeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.PasskeyInfo"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.PasswordChangeResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "tokens": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenPair"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
                     "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.RefreshResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenPair"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
                 },
-                "jti": {
-                    "description": "the ` + "`" + `jti` + "`" + ` (JWT ID) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.7",
-                    "type": "string"
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
                 },
-                "nbf": {
-                    "description": "the ` + "`" + `nbf` + "`" + ` (Not Before) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.5",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/jwt.NumericDate"
-                        }
-                    ]
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
                 },
                 "nick_name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "johndoe"
                 },
-                "role": {
+                "password": {
+                    "type": "string",
+                    "example": "T3st123@"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+34600000000"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenPair"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.UserInfo"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.ResendVerificationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
                     "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "N3wPass@"
                 },
-                "sub": {
-                    "description": "the ` + "`" + `sub` + "`" + ` (Subject) claim. See https://datatracker.ietf.org/doc/html/rfc7519#section-4.1.2",
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "github_com_bikes2road_authentication_internal_domain.LoginRequest": {
+        "github_com_bikes2road_authentication_internal_domain.SMSLoginRequest": {
             "type": "object",
             "required": [
-                "email_or_nick_name",
-                "password"
+                "phone_number"
             ],
             "properties": {
-                "email_or_nick_name": {
+                "phone_number": {
                     "type": "string",
-                    "example": "[EMAIL_ADDRESS] | johndoe"
+                    "example": "+34600000000"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.SMSLoginVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "phone_number"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 },
-                "password": {
+                "phone_number": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "T3st123@"
+                    "example": "+34600000000"
                 }
             }
         },
-        "github_com_bikes2road_authentication_internal_domain.LoginResponse": {
+        "github_com_bikes2road_authentication_internal_domain.SecurityEventType": {
+            "type": "string",
+            "enum": [
+                "user.login.new_device",
+                "user.password.changed",
+                "user.deactivated",
+                "user.sessions.revoked"
+            ],
+            "x-enum-varnames": [
+                "SecurityEventNewDeviceLogin",
+                "SecurityEventPasswordChanged",
+                "SecurityEventUserDeactivated",
+                "SecurityEventSessionsRevoked"
+            ]
+        },
+        "github_com_bikes2road_authentication_internal_domain.SessionInfo": {
             "type": "object",
             "properties": {
-                "tokens": {
-                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenPair"
+                "amr": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pwd",
+                        "otp",
+                        "mfa"
+                    ]
                 },
-                "user": {
-                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.UserInfo"
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current indica la sesión del token con el que se hace la petición",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
                 }
             }
         },
-        "github_com_bikes2road_authentication_internal_domain.RefreshRequest": {
+        "github_com_bikes2road_authentication_internal_domain.SessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SessionInfo"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.SetPasswordRequest": {
             "type": "object",
             "required": [
-                "refresh_token"
+                "password"
             ],
             "properties": {
-                "refresh_token": {
-                    "type": "string"
+                "logout_other_sessions": {
+                    "description": "LogoutOtherSessions cierra el resto de sesiones y devuelve tokens nuevos para la actual",
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "N3wPass@"
                 }
             }
         },
-        "github_com_bikes2road_authentication_internal_domain.RefreshResponse": {
+        "github_com_bikes2road_authentication_internal_domain.SigningKey": {
             "type": "object",
             "properties": {
-                "tokens": {
-                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.TokenPair"
+                "algorithm": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "retired_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SigningKeyStatus"
+                },
+                "verify_until": {
+                    "type": "string"
                 }
             }
         },
+        "github_com_bikes2road_authentication_internal_domain.SigningKeyStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "retired"
+            ],
+            "x-enum-varnames": [
+                "SigningKeyActive",
+                "SigningKeyRetired"
+            ]
+        },
         "github_com_bikes2road_authentication_internal_domain.TokenPair": {
             "type": "object",
             "properties": {
@@ -370,6 +3946,50 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_bikes2road_authentication_internal_domain.TokenType": {
+            "type": "string",
+            "enum": [
+                "access",
+                "refresh",
+                "mfa_challenge"
+            ],
+            "x-enum-varnames": [
+                "AccessToken",
+                "RefreshToken",
+                "MFAChallengeToken"
+            ]
+        },
+        "github_com_bikes2road_authentication_internal_domain.UserIdentitiesResponse": {
+            "type": "object",
+            "properties": {
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.UserIdentity"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.UserIdentity": {
+            "type": "object",
+            "properties": {
+                "email_at_link": {
+                    "type": "string"
+                },
+                "linked_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_subject": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_bikes2road_authentication_internal_domain.UserInfo": {
             "type": "object",
             "properties": {
@@ -379,6 +3999,9 @@ const docTemplate = `{
                 "first_name": {
                     "type": "string"
                 },
+                "has_password": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -415,35 +4038,168 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_bikes2road_authentication_internal_ports.UserInfoOAuth": {
+        "github_com_bikes2road_authentication_internal_domain.WebAuthnOptionsResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "options": {
+                    "description": "Options es el objeto publicKey de la especificación WebAuthn",
+                    "type": "object"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.WebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebhookDeliveryInfo"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.WebhookDeliveryInfo": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "first_name": {
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
                     "type": "string"
                 },
+                "event_type": {
+                    "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SecurityEventType"
+                },
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "last_error": {
                     "type": "string"
                 },
-                "nick_name": {
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "role": {
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebhookDeliveryStatus"
+                        }
+                    ],
+                    "example": "dead"
+                },
+                "subscription_id": {
                     "type": "string"
                 }
             }
         },
+        "github_com_bikes2road_authentication_internal_domain.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "delivered",
+                "dead"
+            ],
+            "x-enum-varnames": [
+                "WebhookDeliveryPending",
+                "WebhookDeliveryDelivered",
+                "WebhookDeliveryDead"
+            ]
+        },
+        "github_com_bikes2road_authentication_internal_domain.WebhookSubscriptionCreated": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SecurityEventType"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.WebhookSubscriptionInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SecurityEventType"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_domain.WebhookSubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.WebhookSubscriptionInfo"
+                    }
+                }
+            }
+        },
+        "github_com_bikes2road_authentication_internal_ports.OAuthLoginRequest": {
+            "type": "object",
+            "required": [
+                "id_token",
+                "provider"
+            ],
+            "properties": {
+                "id_token": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "google"
+                }
+            }
+        },
         "internal_adapters_http.ErrorResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string"
                 },
+                "fields": {
+                    "description": "Fields contiene los errores de validación por campo, si los hay",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "message": {
                     "type": "string"
                 }
@@ -457,6 +4213,17 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_adapters_http.SigningKeysResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bikes2road_authentication_internal_domain.SigningKey"
+                    }
+                }
+            }
+        },
         "jwt.NumericDate": {
             "type": "object",
             "properties": {
@@ -467,6 +4234,9 @@ const docTemplate = `{
         }
     },
     "securityDefinitions": {
+        "BasicAuth": {
+            "type": "basic"
+        },
         "BearerAuth": {
             "description": "Type \"Bearer\" followed by a space and JWT token.",
             "type": "apiKey",
@@ -483,7 +4253,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/auth/v1",
 	Schemes:          []string{},
 	Title:            "Bikes2Road Authentication API",
-	Description:      "Microservicio de autenticación para validar y crear JWT tokens",
+	Description:      "Credenciales client_id / client_secret de un resource server registrado.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
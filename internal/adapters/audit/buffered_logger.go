@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// flushTimeout limita cada escritura de un lote para que un almacén lento no detenga el writer
+const flushTimeout = 10 * time.Second
+
+// BufferedLoggerConfig contiene la configuración del writer asíncrono de eventos
+type BufferedLoggerConfig struct {
+	// BufferSize es el número de eventos pendientes que admite el buffer antes de desbordarse
+	BufferSize int
+	// BatchSize es el número máximo de eventos que se escriben en cada lote
+	BatchSize int
+	// FlushInterval es el tiempo máximo que un evento espera en el buffer
+	FlushInterval time.Duration
+}
+
+// bufferedLogger implementa AuditLogger encolando los eventos y persistiéndolos por lotes en segundo plano
+type bufferedLogger struct {
+	repo     ports.AuthEventRepository
+	fallback ports.AuditLogger
+	events   chan domain.AuthEvent
+	config   BufferedLoggerConfig
+
+	// mu impide encolar eventos una vez que el writer empieza a detenerse
+	mu     sync.RWMutex
+	closed bool
+	stop   context.CancelFunc
+	done   chan struct{}
+}
+
+// NewBufferedLogger crea un AuditLogger que persiste los eventos en el repositorio sin bloquear la petición.
+// Los eventos que no caben en el buffer o cuya escritura falla se envían a fallback para no perderlos.
+// El writer se detiene, tras vaciar el buffer, cuando se cancela el contexto o se llama a Close.
+func NewBufferedLogger(ctx context.Context, repo ports.AuthEventRepository, fallback ports.AuditLogger, config BufferedLoggerConfig) ports.BufferedAuditLogger {
+	ctx, stop := context.WithCancel(ctx)
+	l := &bufferedLogger{
+		repo:     repo,
+		fallback: fallback,
+		events:   make(chan domain.AuthEvent, config.BufferSize),
+		config:   config,
+		stop:     stop,
+		done:     make(chan struct{}),
+	}
+	go l.run(ctx)
+	return l
+}
+
+// Close detiene el writer y espera a que persista los eventos pendientes o a que venza el contexto
+func (l *bufferedLogger) Close(ctx context.Context) error {
+	l.stop()
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Record completa el evento con los datos de la petición y lo encola
+func (l *bufferedLogger) Record(ctx context.Context, event domain.AuthEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	client := domain.ClientInfoFromContext(ctx)
+	if event.IP == "" {
+		event.IP = client.IP
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = client.UserAgent
+	}
+	if event.CorrelationID == "" {
+		event.CorrelationID = domain.CorrelationIDFromContext(ctx)
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		l.fallback.Record(ctx, event)
+		return
+	}
+
+	select {
+	case l.events <- event:
+	default:
+		l.fallback.Record(ctx, event)
+	}
+}
+
+func (l *bufferedLogger) run(ctx context.Context) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.AuthEvent, 0, l.config.BatchSize)
+	for {
+		select {
+		case event := <-l.events:
+			batch = append(batch, event)
+			if len(batch) >= l.config.BatchSize {
+				batch = l.flush(batch)
+			}
+		case <-ticker.C:
+			batch = l.flush(batch)
+		case <-ctx.Done():
+			// A partir de aquí Record envía los eventos al respaldo, así que el buffer solo puede vaciarse
+			l.mu.Lock()
+			l.closed = true
+			l.mu.Unlock()
+			for {
+				select {
+				case event := <-l.events:
+					batch = append(batch, event)
+				default:
+					l.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush escribe el lote y retorna el slice vacío para reutilizarlo
+func (l *bufferedLogger) flush(batch []domain.AuthEvent) []domain.AuthEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	// El contexto de la petición ya terminó: la escritura usa el suyo propio
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := l.repo.CreateBatch(ctx, batch); err != nil {
+		log.Printf("failed to persist %d audit events: %v", len(batch), err)
+		for _, event := range batch {
+			l.fallback.Record(ctx, event)
+		}
+	}
+	return batch[:0]
+}
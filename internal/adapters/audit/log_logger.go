@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// logAuditLogger implementa AuditLogger escribiendo los eventos en el log estándar
+type logAuditLogger struct{}
+
+// NewLogAuditLogger crea un AuditLogger que escribe los eventos en el log
+func NewLogAuditLogger() ports.AuditLogger {
+	return &logAuditLogger{}
+}
+
+// Record escribe el evento en el log
+func (l *logAuditLogger) Record(ctx context.Context, event domain.AuthEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	log.Printf("audit event=%s outcome=%s user_id=%s identifier=%q ip=%s correlation_id=%s reason=%q metadata=%v at=%s",
+		event.Type, event.Outcome, event.UserID, event.Identifier, event.IP, event.CorrelationID,
+		event.Reason, event.Metadata, event.OccurredAt.Format(time.RFC3339))
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type adminHandler struct {
+	keyring        ports.Keyring
+	userService    ports.UserService
+	auditService   ports.AuditService
+	accountService ports.AccountService
+}
+
+// NewAdminHandler crea una nueva instancia del handler de administración
+func NewAdminHandler(
+	keyring ports.Keyring,
+	userService ports.UserService,
+	auditService ports.AuditService,
+	accountService ports.AccountService,
+) ports.AdminHandler {
+	return &adminHandler{
+		keyring:        keyring,
+		userService:    userService,
+		auditService:   auditService,
+		accountService: accountService,
+	}
+}
+
+// ListSigningKeys godoc
+// @Summary      Listar claves de firma
+// @Description  Lista la clave activa y las claves retiradas que todavía verifican tokens. No incluye material criptográfico
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} SigningKeysResponse "Claves de firma"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Router       /admin/keys [get]
+func (h *adminHandler) ListSigningKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, SigningKeysResponse{
+		Keys: h.keyring.Keys(),
+	})
+}
+
+// RotateSigningKey godoc
+// @Summary      Rotar la clave de firma
+// @Description  Genera una nueva clave activa. La anterior deja de firmar pero verifica tokens hasta el fin del periodo de gracia
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SigningKey "Nueva clave activa"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/keys/rotate [post]
+func (h *adminHandler) RotateSigningKey(c *gin.Context) {
+	key, err := h.keyring.Rotate(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "An unexpected error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// UnlockUser godoc
+// @Summary      Desbloquear una cuenta
+// @Description  Levanta el bloqueo por intentos de login fallidos y reinicia el contador de fallos de la cuenta
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "ID del usuario"
+// @Success      200 {object} domain.MessageResponse "Cuenta desbloqueada"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      404 {object} ErrorResponse "Usuario no encontrado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/users/{id}/unlock [post]
+func (h *adminHandler) UnlockUser(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.userService.UnlockUser(c.Request.Context(), c.Param("id"), claims.UserID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Not Found",
+				Message: "User not found",
+			})
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{
+		Message: "Account unlocked",
+	})
+}
+
+// DeactivateUser godoc
+// @Summary      Desactivar una cuenta
+// @Description  Impide que la cuenta vuelva a iniciar sesión, cierra todas sus sesiones y revoca sus tokens. Notifica los webhooks user.deactivated y user.sessions.revoked. Desactivar una cuenta ya inactiva no tiene efecto
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "ID del usuario"
+// @Success      200 {object} domain.MessageResponse "Cuenta desactivada"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      404 {object} ErrorResponse "Usuario no encontrado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/users/{id}/deactivate [post]
+func (h *adminHandler) DeactivateUser(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.accountService.Deactivate(c.Request.Context(), c.Param("id"), claims.UserID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Not Found",
+				Message: "User not found",
+			})
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{
+		Message: "Account deactivated",
+	})
+}
+
+// ListAuthEvents godoc
+// @Summary      Consultar el registro de auditoría
+// @Description  Lista los eventos de autenticación del más reciente al más antiguo. Todos los filtros son opcionales y se combinan; para la página siguiente se envía next_cursor como cursor con los mismos filtros
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type query string false "Tipo de evento" example(login)
+// @Param        user_id query string false "ID del usuario"
+// @Param        identifier query string false "Email, nick, teléfono o proveedor con el que se intentó la operación"
+// @Param        ip query string false "IP del cliente"
+// @Param        outcome query string false "Resultado" Enums(success, failure, mfa_required)
+// @Param        correlation_id query string false "X-Request-ID de la petición"
+// @Param        from query string false "Desde (RFC 3339, incluido)"
+// @Param        to query string false "Hasta (RFC 3339, excluido)"
+// @Param        cursor query string false "next_cursor de la página anterior"
+// @Param        limit query int false "Eventos por página (1-200, 50 por defecto)"
+// @Success      200 {object} domain.AuthEventsResponse "Página de eventos"
+// @Failure      400 {object} ErrorResponse "Parámetros mal formados"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      422 {object} ErrorResponse "Cursor, límite o rango de fechas inválidos"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/audit/events [get]
+func (h *adminHandler) ListAuthEvents(c *gin.Context) {
+	var query domain.AuthEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	events, err := h.auditService.Query(c.Request.Context(), query)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// SigningKeysResponse representa el listado de claves de firma
+type SigningKeysResponse struct {
+	Keys []*domain.SigningKey `json:"keys"`
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type discoveryHandler struct {
+	jwtService ports.JWTService
+	baseURL    string
+}
+
+// NewDiscoveryHandler crea una nueva instancia del handler de descubrimiento.
+// baseURL es la URL pública del servicio con la que se construyen los endpoints anunciados.
+func NewDiscoveryHandler(jwtService ports.JWTService, baseURL string) ports.DiscoveryHandler {
+	return &discoveryHandler{
+		jwtService: jwtService,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// OpenIDConfiguration godoc
+// @Summary      Documento de descubrimiento OpenID Connect
+// @Description  Describe el emisor, el JWKS, los endpoints de userinfo e introspección, los endpoints propios de login, refresh y logout y los scopes y claims soportados
+// @Tags         discovery
+// @Produce      json
+// @Success      200 {object} domain.OpenIDConfiguration "Metadatos OpenID Connect"
+// @Router       /.well-known/openid-configuration [get]
+func (h *discoveryHandler) OpenIDConfiguration(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, domain.OpenIDConfiguration{
+		Issuer:                h.jwtService.Issuer(),
+		JWKSURI:               h.baseURL + "/.well-known/jwks.json",
+		UserInfoEndpoint:      h.baseURL + "/v1/userinfo",
+		IntrospectionEndpoint: h.baseURL + "/v1/introspect",
+		LoginEndpoint:         h.baseURL + "/v1/login",
+		RefreshEndpoint:       h.baseURL + "/v1/refresh",
+		LogoutEndpoint:        h.baseURL + "/v1/logout",
+		SubjectTypesSupported: []string{"public"},
+		ScopesSupported:       []string{"openid", "profile", "email"},
+		ClaimsSupported: []string{
+			"sub", "iss", "aud", "exp", "iat", "nbf", "jti",
+			"email", "email_verified", "given_name", "family_name", "preferred_username",
+			"nick_name", "role", "token_type", "scope", "client_id", "auth_time", "amr", "acr", "sid",
+		},
+		ACRValuesSupported:                        []string{domain.ACRSingleFactor, domain.ACRMultiFactor},
+		IntrospectionEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+// JWKS godoc
+// @Summary      Claves públicas de verificación
+// @Description  Retorna el JSON Web Key Set con las claves públicas para verificar los tokens emitidos. Vacío cuando se firma con HS256
+// @Tags         discovery
+// @Produce      json
+// @Success      200 {object} domain.JWKS "Claves públicas"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /.well-known/jwks.json [get]
+func (h *discoveryHandler) JWKS(c *gin.Context) {
+	jwks, err := h.jwtService.JWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "An unexpected error occurred",
+		})
+		return
+	}
+
+	// Los verificadores pueden cachear las claves durante un tiempo corto
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwks)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type emailHandler struct {
+	emailVerifier ports.EmailVerificationService
+}
+
+// NewEmailHandler crea una nueva instancia del handler de verificación de email
+func NewEmailHandler(emailVerifier ports.EmailVerificationService) ports.EmailHandler {
+	return &emailHandler{
+		emailVerifier: emailVerifier,
+	}
+}
+
+// VerifyEmail godoc
+// @Summary      Verificar email
+// @Description  Consume el token de un solo uso del enlace de verificación y marca el email como verificado. Acepta el token como query (GET) o en el body (POST)
+// @Tags         email
+// @Accept       json
+// @Produce      json
+// @Param        token query string false "Token de verificación (GET)"
+// @Param        request body domain.EmailVerificationRequest false "Token de verificación (POST)"
+// @Success      200 {object} domain.MessageResponse "Email verificado"
+// @Failure      400 {object} ErrorResponse "Token inválido, expirado o ya usado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /email/verify [get]
+// @Router       /email/verify [post]
+func (h *emailHandler) VerifyEmail(c *gin.Context) {
+	var req domain.EmailVerificationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.emailVerifier.Verify(c.Request.Context(), req.Token); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{
+		Message: "Email verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary      Reenviar email de verificación
+// @Description  Envía un nuevo enlace de verificación. La respuesta es siempre la misma exista o no la cuenta
+// @Tags         email
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.ResendVerificationRequest true "Email de la cuenta"
+// @Success      202 {object} domain.MessageResponse "Solicitud aceptada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /email/resend [post]
+func (h *emailHandler) ResendVerification(c *gin.Context) {
+	var req domain.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.emailVerifier.Resend(c.Request.Context(), req.Email); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, domain.MessageResponse{
+		Message: "If the account exists and is not verified, a verification email has been sent",
+	})
+}
@@ -2,13 +2,19 @@ package http
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
 	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
 	"github.com/gin-gonic/gin"
 )
 
+// magicLinkNonceCookie guarda el nonce que liga el enlace de login al navegador que lo solicitó
+const magicLinkNonceCookie = "magic_link_nonce"
+
 type authHandler struct {
 	authService ports.AuthService
 }
@@ -22,7 +28,7 @@ func NewAuthHandler(authService ports.AuthService) ports.AuthHandler {
 
 // Login godoc
 // @Summary      Login de usuario
-// @Description  Autentica un usuario con email y password, retorna tokens JWT
+// @Description  Autentica un usuario con email y password, retorna tokens JWT. Si el usuario tiene un segundo factor, retorna mfa_required y un challenge a completar en /login/mfa
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -30,6 +36,8 @@ func NewAuthHandler(authService ports.AuthService) ports.AuthHandler {
 // @Success      200 {object} domain.LoginResponse "Login exitoso"
 // @Failure      400 {object} ErrorResponse "Request inválido"
 // @Failure      401 {object} ErrorResponse "Credenciales inválidas"
+// @Failure      423 {object} ErrorResponse "Cuenta bloqueada temporalmente por intentos fallidos, solo con la contraseña correcta (ver cabecera Retry-After)"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor"
 // @Router       /login [post]
 func (h *authHandler) Login(c *gin.Context) {
@@ -47,7 +55,261 @@ func (h *authHandler) Login(c *gin.Context) {
 		Password:        req.Password,
 	})
 	if err != nil {
-		h.handleError(c, err)
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// LoginMFA godoc
+// @Summary      Segundo factor del login
+// @Description  Completa el login con el challenge devuelto por /login y un código TOTP o de recuperación, o la aserción de una passkey iniciada en /login/mfa/webauthn. Retorna tokens JWT
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFALoginRequest true "Challenge y código o passkey"
+// @Success      200 {object} domain.LoginResponse "Login exitoso"
+// @Failure      400 {object} ErrorResponse "Request inválido, ceremonia WebAuthn expirada o factor ya usado como primer factor"
+// @Failure      401 {object} ErrorResponse "Challenge, código o passkey inválidos"
+// @Failure      423 {object} ErrorResponse "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/mfa [post]
+func (h *authHandler) LoginMFA(c *gin.Context) {
+	var req domain.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.LoginMFA(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BeginMFAPasskey godoc
+// @Summary      Iniciar el segundo factor con passkey
+// @Description  Retorna las opciones para navigator.credentials.get() limitadas a las passkeys del usuario del challenge. La aserción se envía a /login/mfa junto con session_id
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFAPasskeyRequest true "Challenge del login"
+// @Success      200 {object} domain.WebAuthnOptionsResponse "Opciones de la ceremonia"
+// @Failure      400 {object} ErrorResponse "Request inválido o el primer factor ya fue una passkey"
+// @Failure      401 {object} ErrorResponse "Challenge inválido o expirado"
+// @Failure      404 {object} ErrorResponse "El usuario no tiene passkeys"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/mfa/webauthn [post]
+func (h *authHandler) BeginMFAPasskey(c *gin.Context) {
+	var req domain.MFAPasskeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	options, err := h.authService.BeginMFAPasskey(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// BeginPasskeyLogin godoc
+// @Summary      Iniciar login con passkey
+// @Description  Retorna las opciones para navigator.credentials.get() de un login sin contraseña; el autenticador ofrece las passkeys guardadas para este dominio
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} domain.WebAuthnOptionsResponse "Opciones de la ceremonia"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/passkey/begin [post]
+func (h *authHandler) BeginPasskeyLogin(c *gin.Context) {
+	options, err := h.authService.BeginPasskeyLogin(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// LoginPasskey godoc
+// @Summary      Completar login con passkey
+// @Description  Verifica la aserción de la passkey y retorna tokens JWT. Si el autenticador no verificó al usuario (PIN o biometría) y tiene otro factor, retorna mfa_required
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.PasskeyAssertionRequest true "Sesión y aserción del autenticador"
+// @Success      200 {object} domain.LoginResponse "Login exitoso"
+// @Failure      400 {object} ErrorResponse "Request inválido o ceremonia expirada"
+// @Failure      401 {object} ErrorResponse "Passkey inválida o usuario inactivo"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/passkey/finish [post]
+func (h *authHandler) LoginPasskey(c *gin.Context) {
+	var req domain.PasskeyAssertionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.LoginPasskey(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RequestMagicLink godoc
+// @Summary      Solicitar enlace de login
+// @Description  Envía un enlace de un solo uso para iniciar sesión sin contraseña y fija la cookie magic_link_nonce que liga el enlace a este navegador. La respuesta es siempre la misma exista o no la cuenta
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MagicLinkRequest true "Email de la cuenta"
+// @Success      202 {object} domain.MessageResponse "Solicitud aceptada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/magic-link [post]
+func (h *authHandler) RequestMagicLink(c *gin.Context) {
+	var req domain.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	nonce, err := h.authService.RequestMagicLink(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Cookie de sesión: la caducidad del enlace la controla el servidor
+	setMagicLinkNonceCookie(c, nonce, 0)
+	c.JSON(http.StatusAccepted, domain.MessageResponse{
+		Message: "If the account exists, a login link has been sent",
+	})
+}
+
+// LoginMagicLink godoc
+// @Summary      Login con enlace
+// @Description  Consume el enlace de login junto con la cookie magic_link_nonce y retorna tokens JWT con amr=["email"]. Si el usuario tiene un segundo factor, retorna mfa_required. Acepta el token como query (GET) o en el body (POST)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token query string false "Token del enlace (GET)"
+// @Param        request body domain.MagicLinkVerifyRequest false "Token del enlace (POST)"
+// @Success      200 {object} domain.LoginResponse "Login exitoso"
+// @Failure      400 {object} ErrorResponse "Token inválido, expirado, ya usado o abierto en otro navegador"
+// @Failure      401 {object} ErrorResponse "Usuario inactivo"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/magic-link/verify [get]
+// @Router       /login/magic-link/verify [post]
+func (h *authHandler) LoginMagicLink(c *gin.Context) {
+	var req domain.MagicLinkVerifyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	nonce, _ := c.Cookie(magicLinkNonceCookie)
+	response, err := h.authService.LoginMagicLink(c.Request.Context(), req, nonce)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	setMagicLinkNonceCookie(c, "", -1)
+	c.JSON(http.StatusOK, response)
+}
+
+// RequestSMSCode godoc
+// @Summary      Solicitar código de login por SMS
+// @Description  Envía un código de 6 dígitos al teléfono (formato internacional, se normaliza a E.164) si pertenece a una cuenta activa. La respuesta es siempre la misma exista o no la cuenta
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.SMSLoginRequest true "Teléfono de la cuenta"
+// @Success      202 {object} domain.MessageResponse "Solicitud aceptada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      422 {object} ErrorResponse "El teléfono no está en formato internacional"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/sms [post]
+func (h *authHandler) RequestSMSCode(c *gin.Context) {
+	var req domain.SMSLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.RequestSMSCode(c.Request.Context(), req); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, domain.MessageResponse{
+		Message: "If the phone number belongs to an account, a login code has been sent",
+	})
+}
+
+// LoginSMS godoc
+// @Summary      Login con código SMS
+// @Description  Verifica el código recibido por SMS y retorna tokens JWT con amr=["sms"]. Si el usuario tiene un segundo factor, retorna mfa_required
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.SMSLoginVerifyRequest true "Teléfono y código"
+// @Success      200 {object} domain.LoginResponse "Login exitoso"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Código inválido, expirado o sin intentos, o usuario inactivo"
+// @Failure      423 {object} ErrorResponse "Cuenta bloqueada temporalmente (ver cabecera Retry-After)"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /login/sms/verify [post]
+func (h *authHandler) LoginSMS(c *gin.Context) {
+	var req domain.SMSLoginVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.LoginSMS(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 
@@ -56,18 +318,21 @@ func (h *authHandler) Login(c *gin.Context) {
 
 // OauthLogin godoc
 // @Summary      OAuth login de usuario
-// @Description  Autentica un usuario con Google OAuth y retorna tokens JWT
+// @Description  Verifica el ID token emitido por el proveedor (Google) y retorna tokens JWT del usuario asociado
 // @Tags         auth
 // @Accept       json
 // @Produce      json
-// @Param        request body ports.UserInfoOAuth true "Credenciales de OAuth"
+// @Param        request body ports.OAuthLoginRequest true "ID token del proveedor"
 // @Success      200 {object} domain.LoginResponse "Login exitoso"
-// @Failure      400 {object} ErrorResponse "Request inválido"
-// @Failure      401 {object} ErrorResponse "Credenciales inválidas"
+// @Failure      400 {object} ErrorResponse "Request inválido o proveedor no soportado"
+// @Failure      401 {object} ErrorResponse "ID token inválido, email no verificado o usuario inexistente"
+// @Failure      409 {object} ErrorResponse "Existe una cuenta con ese email sin verificar; hay que iniciar sesión en ella y vincular la identidad"
+// @Failure      422 {object} ErrorResponse "Un alta concurrente tomó el nick_name derivado (fields.nick_name); se puede reintentar"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor"
 // @Router       /oauth/login [post]
 func (h *authHandler) OauthLogin(c *gin.Context) {
-	var req ports.UserInfoOAuth
+	var req ports.OAuthLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid request",
@@ -78,13 +343,45 @@ func (h *authHandler) OauthLogin(c *gin.Context) {
 
 	response, err := h.authService.OauthLogin(c.Request.Context(), req)
 	if err != nil {
-		h.handleError(c, err)
+		handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// Register godoc
+// @Summary      Registro de usuario
+// @Description  Crea una cuenta con email y contraseña. Los errores de validación se informan por campo en "fields"
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.RegisterRequest true "Datos del nuevo usuario"
+// @Success      201 {object} domain.RegisterResponse "Usuario registrado"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      422 {object} ErrorResponse "Errores de validación por campo"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /register [post]
+func (h *authHandler) Register(c *gin.Context) {
+	var req domain.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.Register(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
 // Validate godoc
 // @Summary      Validar token JWT
 // @Description  Valida un token JWT y retorna sus claims si es válido
@@ -94,6 +391,7 @@ func (h *authHandler) OauthLogin(c *gin.Context) {
 // @Param        request body domain.ValidateRequest true "Token a validar"
 // @Success      200 {object} domain.ValidateResponse "Token validado"
 // @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor"
 // @Router       /validate [post]
 func (h *authHandler) Validate(c *gin.Context) {
@@ -108,7 +406,7 @@ func (h *authHandler) Validate(c *gin.Context) {
 
 	response, err := h.authService.ValidateToken(c.Request.Context(), req.Token)
 	if err != nil {
-		h.handleError(c, err)
+		handleError(c, err)
 		return
 	}
 
@@ -125,6 +423,7 @@ func (h *authHandler) Validate(c *gin.Context) {
 // @Success      200 {object} domain.RefreshResponse "Tokens refrescados"
 // @Failure      400 {object} ErrorResponse "Request inválido"
 // @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor"
 // @Router       /refresh [post]
 func (h *authHandler) Refresh(c *gin.Context) {
@@ -139,16 +438,236 @@ func (h *authHandler) Refresh(c *gin.Context) {
 
 	response, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		h.handleError(c, err)
+		handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// Logout godoc
+// @Summary      Logout de la sesión actual
+// @Description  Revoca el access token enviado y, si se incluye, el refresh token de la misma sesión
+// @Tags         auth
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body domain.LogoutRequest false "Refresh token de la sesión"
+// @Success      204 "Sesión cerrada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /logout [post]
+func (h *authHandler) Logout(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), claims, req.RefreshToken); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary      Logout de todas las sesiones
+// @Description  Revoca todos los refresh tokens del usuario y todos los tokens emitidos hasta el momento
+// @Tags         auth
+// @Security     BearerAuth
+// @Success      204 "Sesiones cerradas"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /logout/all [post]
+func (h *authHandler) LogoutAll(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.authService.LogoutAll(c.Request.Context(), claims); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword godoc
+// @Summary      Cambiar contraseña
+// @Description  Cambia la contraseña del usuario autenticado comprobando la actual. Opcionalmente cierra el resto de sesiones y devuelve tokens nuevos
+// @Tags         password
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.ChangePasswordRequest true "Contraseña actual y nueva"
+// @Success      200 {object} domain.PasswordChangeResponse "Contraseña cambiada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      409 {object} ErrorResponse "La cuenta no tiene contraseña"
+// @Failure      422 {object} ErrorResponse "Contraseña actual incorrecta o nueva contraseña inválida"
+// @Failure      423 {object} ErrorResponse "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /password/change [post]
+func (h *authHandler) ChangePassword(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.ChangePassword(c.Request.Context(), claims, req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetPassword godoc
+// @Summary      Establecer contraseña
+// @Description  Establece la primera contraseña de un usuario que solo usa OAuth. Exige haberse autenticado recientemente
+// @Tags         password
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.SetPasswordRequest true "Nueva contraseña"
+// @Success      200 {object} domain.PasswordChangeResponse "Contraseña establecida"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o autenticación no reciente"
+// @Failure      409 {object} ErrorResponse "La cuenta ya tiene contraseña"
+// @Failure      422 {object} ErrorResponse "La contraseña no cumple la política"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /password/set [post]
+func (h *authHandler) SetPassword(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.SetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.SetPassword(c.Request.Context(), claims, req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UserInfo godoc
+// @Summary      Información del usuario (OpenID Connect)
+// @Description  Retorna los claims estándar de OpenID Connect del usuario del access token
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.OIDCUserInfo "Información del usuario"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /userinfo [get]
+func (h *authHandler) UserInfo(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	userInfo, err := h.authService.UserInfo(c.Request.Context(), claims)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, userInfo)
+}
+
+// Introspect godoc
+// @Summary      Introspección de token (RFC 7662)
+// @Description  Informa si un access o refresh token está activo y retorna sus metadatos. Requiere credenciales de un resource server registrado
+// @Tags         auth
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Security     BasicAuth
+// @Param        token formData string true "Token a inspeccionar"
+// @Param        token_type_hint formData string false "access_token o refresh_token"
+// @Success      200 {object} domain.IntrospectionResponse "Estado del token"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Credenciales de cliente inválidas"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /introspect [post]
+func (h *authHandler) Introspect(c *gin.Context) {
+	var req domain.IntrospectionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.Introspect(c.Request.Context(), req.Token, req.TokenTypeHint)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// RFC 7662: la respuesta no debe ser cacheada
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
 // handleError maneja los errores y retorna la respuesta HTTP apropiada
-func (h *authHandler) handleError(c *gin.Context, err error) {
+func handleError(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	var lockedErr *domain.AccountLockedError
+
 	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "Validation failed",
+			Message: "One or more fields are invalid",
+			Fields:  validationErr.Fields,
+		})
+	case errors.As(err, &lockedErr):
+		// Retry-After en segundos, redondeado hacia arriba para no invitar a reintentar antes de tiempo
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		c.JSON(http.StatusLocked, ErrorResponse{
+			Error:   "Locked",
+			Message: "Account is temporarily locked due to too many failed login attempts",
+		})
 	case errors.Is(err, domain.ErrInvalidCredentials):
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
 			Error:   "Unauthorized",
@@ -174,11 +693,171 @@ func (h *authHandler) handleError(c *gin.Context, err error) {
 			Error:   "Unauthorized",
 			Message: "Token has expired",
 		})
+	case errors.Is(err, domain.ErrInvalidTokenType):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid token type",
+		})
+	case errors.Is(err, domain.ErrInvalidTokenAudience):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid token audience",
+		})
+	case errors.Is(err, domain.ErrRefreshTokenReused):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Refresh token has already been used",
+		})
+	case errors.Is(err, domain.ErrTokenRevoked):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Token has been revoked",
+		})
 	case errors.Is(err, domain.ErrTokenMalformed):
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
 			Error:   "Unauthorized",
 			Message: "Token is malformed",
 		})
+	case errors.Is(err, domain.ErrInvalidIDToken):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid ID token",
+		})
+	case errors.Is(err, domain.ErrIdentityEmailNotVerified):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Identity email is not verified",
+		})
+	case errors.Is(err, domain.ErrUnsupportedProvider):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: "Unsupported identity provider",
+		})
+	case errors.Is(err, domain.ErrIdentityNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not Found",
+			Message: "Identity not found",
+		})
+	case errors.Is(err, domain.ErrIdentityAlreadyLinked):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "Identity is already linked",
+		})
+	case errors.Is(err, domain.ErrIdentityLinkRequired):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "An account with this email already exists; sign in to it and link the identity",
+		})
+	case errors.Is(err, domain.ErrLastLoginMethod):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "Cannot remove the last login method of the account",
+		})
+	case errors.Is(err, domain.ErrEmailDomainNotAllowed):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Email domain is not allowed",
+		})
+	case errors.Is(err, domain.ErrOneTimeTokenInvalid):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: "Token is invalid, expired or already used",
+		})
+	case errors.Is(err, domain.ErrInvalidSMSCode):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid or expired SMS code",
+		})
+	case errors.Is(err, domain.ErrMagicLinkBrowserMismatch):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: "Open the login link in the same browser where it was requested",
+		})
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Email is not verified",
+		})
+	case errors.Is(err, domain.ErrPasswordAlreadySet):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "Password is already set",
+		})
+	case errors.Is(err, domain.ErrPasswordNotSet):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "Password is not set",
+		})
+	case errors.Is(err, domain.ErrReauthenticationRequired):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Recent authentication required",
+		})
+	case errors.Is(err, domain.ErrMFANotEnrolled):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "MFA enrollment not found, start the enrollment first",
+		})
+	case errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "MFA is already enabled",
+		})
+	case errors.Is(err, domain.ErrMFANotEnabled):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "MFA is not enabled",
+		})
+	case errors.Is(err, domain.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid MFA code",
+		})
+	case errors.Is(err, domain.ErrPasskeyNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not Found",
+			Message: "Passkey not found",
+		})
+	case errors.Is(err, domain.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not Found",
+			Message: "Session not found",
+		})
+	case errors.Is(err, domain.ErrWebhookSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not Found",
+			Message: "Webhook subscription not found",
+		})
+	case errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not Found",
+			Message: "Webhook delivery not found",
+		})
+	case errors.Is(err, domain.ErrPasskeyAlreadyRegistered):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: "Passkey is already registered",
+		})
+	case errors.Is(err, domain.ErrInvalidPasskey):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid passkey",
+		})
+	case errors.Is(err, domain.ErrMFAFactorNotAllowed):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Bad Request",
+			Message: "This factor was already used to start the login, choose another one",
+		})
+	case errors.Is(err, domain.ErrWebAuthnSessionNotFound):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Bad Request",
+			Message: "WebAuthn ceremony not found or expired, start it again",
+		})
+	case errors.Is(err, domain.ErrUnauthorized):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Authentication required",
+		})
 	case errors.Is(err, domain.ErrUserServiceUnavailable):
 		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
 			Error:   "Service Unavailable",
@@ -196,4 +875,14 @@ func (h *authHandler) handleError(c *gin.Context, err error) {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
+	// Fields contiene los errores de validación por campo, si los hay
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// setMagicLinkNonceCookie fija (o con maxAge negativo borra) la cookie del nonce del enlace de login.
+// SameSite=Lax permite enviarla al abrir el enlace desde el cliente de correo.
+func setMagicLinkNonceCookie(c *gin.Context, nonce string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkNonceCookie, nonce, maxAge, "/", "", secure, true)
 }
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type identityHandler struct {
+	identityService ports.IdentityService
+}
+
+// NewIdentityHandler crea una nueva instancia del handler de identidades externas
+func NewIdentityHandler(identityService ports.IdentityService) ports.IdentityHandler {
+	return &identityHandler{
+		identityService: identityService,
+	}
+}
+
+// ListIdentities godoc
+// @Summary      Listar proveedores vinculados
+// @Description  Lista las identidades de proveedores externos vinculadas a la cuenta autenticada
+// @Tags         identities
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.UserIdentitiesResponse "Identidades vinculadas"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /identities [get]
+func (h *identityHandler) ListIdentities(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	identities, err := h.identityService.List(c.Request.Context(), claims.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.UserIdentitiesResponse{
+		Identities: identities,
+	})
+}
+
+// LinkIdentity godoc
+// @Summary      Vincular un proveedor
+// @Description  Verifica el ID token del proveedor y lo vincula a la cuenta autenticada
+// @Tags         identities
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.LinkIdentityRequest true "ID token del proveedor"
+// @Success      201 {object} domain.UserIdentity "Identidad vinculada"
+// @Failure      400 {object} ErrorResponse "Request inválido o proveedor no soportado"
+// @Failure      401 {object} ErrorResponse "Token o ID token inválido"
+// @Failure      409 {object} ErrorResponse "La identidad o el proveedor ya están vinculados"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /identities [post]
+func (h *identityHandler) LinkIdentity(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.LinkIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	identity, err := h.identityService.Link(c.Request.Context(), claims.UserID, req.Provider, req.IDToken)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, identity)
+}
+
+// UnlinkIdentity godoc
+// @Summary      Desvincular un proveedor
+// @Description  Desvincula el proveedor de la cuenta autenticada. Se rechaza si la cuenta quedaría sin contraseña y sin proveedores
+// @Tags         identities
+// @Security     BearerAuth
+// @Param        provider path string true "Proveedor" example(google)
+// @Success      204 "Proveedor desvinculado"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      404 {object} ErrorResponse "El proveedor no está vinculado"
+// @Failure      409 {object} ErrorResponse "Es el último método de inicio de sesión"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /identities/{provider} [delete]
+func (h *identityHandler) UnlinkIdentity(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.identityService.Unlink(c.Request.Context(), claims.UserID, c.Param("provider")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type mfaHandler struct {
+	mfaService ports.MFAService
+}
+
+// NewMFAHandler crea una nueva instancia del handler de segundo factor
+func NewMFAHandler(mfaService ports.MFAService) ports.MFAHandler {
+	return &mfaHandler{
+		mfaService: mfaService,
+	}
+}
+
+// Status godoc
+// @Summary      Estado del segundo factor
+// @Description  Indica si la cuenta autenticada tiene MFA activo y cuántos códigos de recuperación le quedan
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.MFAStatusResponse "Estado del segundo factor"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /mfa [get]
+func (h *mfaHandler) Status(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	status, err := h.mfaService.Status(c.Request.Context(), claims.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// EnrollTOTP godoc
+// @Summary      Iniciar el registro TOTP
+// @Description  Genera un secreto TOTP y lo devuelve como otpauth URI y código QR (PNG). No se activa hasta confirmarlo con un primer código
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.MFAEnrollmentResponse "Secreto pendiente de confirmar"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      409 {object} ErrorResponse "MFA ya está activo"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /mfa/totp/enroll [post]
+func (h *mfaHandler) EnrollTOTP(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(c.Request.Context(), claims.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTOTP godoc
+// @Summary      Confirmar el registro TOTP
+// @Description  Activa MFA con el primer código generado por la app y devuelve diez códigos de recuperación de un solo uso, que solo se muestran esta vez
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.MFACodeRequest true "Código TOTP"
+// @Success      200 {object} domain.MFARecoveryCodesResponse "MFA activado"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      409 {object} ErrorResponse "No hay un registro pendiente o MFA ya está activo"
+// @Failure      422 {object} ErrorResponse "Código incorrecto"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /mfa/totp/confirm [post]
+func (h *mfaHandler) ConfirmTOTP(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	codes, err := h.mfaService.Confirm(c.Request.Context(), claims.UserID, req.Code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+// Disable godoc
+// @Summary      Desactivar MFA
+// @Description  Desactiva el segundo factor tras comprobar un código TOTP o de recuperación
+// @Tags         mfa
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body domain.MFACodeRequest true "Código TOTP o de recuperación"
+// @Success      204 "MFA desactivado"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      409 {object} ErrorResponse "MFA no está activo"
+// @Failure      422 {object} ErrorResponse "Código incorrecto o ya usado"
+// @Failure      423 {object} ErrorResponse "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /mfa/disable [post]
+func (h *mfaHandler) Disable(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.mfaService.Disable(c.Request.Context(), claims.UserID, req.Code); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerar códigos de recuperación
+// @Description  Invalida los códigos de recuperación anteriores y genera diez nuevos tras comprobar un código TOTP o de recuperación
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.MFACodeRequest true "Código TOTP o de recuperación"
+// @Success      200 {object} domain.MFARecoveryCodesResponse "Nuevos códigos de recuperación"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      409 {object} ErrorResponse "MFA no está activo"
+// @Failure      422 {object} ErrorResponse "Código incorrecto o ya usado"
+// @Failure      423 {object} ErrorResponse "Cuenta bloqueada temporalmente por intentos fallidos (ver cabecera Retry-After)"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /mfa/recovery-codes [post]
+func (h *mfaHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), claims.UserID, req.Code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// claimsContextKey es la clave bajo la que se guardan los claims del usuario autenticado
+const claimsContextKey = "auth.claims"
+
+// RequireAuth exige un access token válido y no revocado en la cabecera Authorization.
+// Los claims quedan disponibles para los handlers mediante ClaimsFromContext.
+func RequireAuth(authService ports.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := BearerToken(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Missing bearer token",
+			})
+			return
+		}
+
+		claims, err := authService.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			abortWithAuthError(c, err)
+			return
+		}
+
+		c.Set(claimsContextKey, claims)
+		c.Next()
+	}
+}
+
+// RequireRole exige que el usuario autenticado por RequireAuth tenga alguno de los roles indicados
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Authentication required",
+			})
+			return
+		}
+
+		if !slices.Contains(roles, claims.Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "Insufficient permissions",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ClaimsFromContext retorna los claims guardados por RequireAuth
+func ClaimsFromContext(c *gin.Context) (*domain.JWTClaims, bool) {
+	value, exists := c.Get(claimsContextKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*domain.JWTClaims)
+	return claims, ok
+}
+
+// BearerToken extrae el token de la cabecera "Authorization: Bearer <token>"
+func BearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// abortWithAuthError traduce los errores de validación del token a la respuesta HTTP
+func abortWithAuthError(c *gin.Context, err error) {
+	var message string
+	switch {
+	case errors.Is(err, domain.ErrTokenExpired):
+		message = "Token has expired"
+	case errors.Is(err, domain.ErrTokenRevoked):
+		message = "Token has been revoked"
+	case errors.Is(err, domain.ErrInvalidTokenType):
+		message = "Invalid token type"
+	case errors.Is(err, domain.ErrInvalidTokenAudience):
+		message = "Invalid token audience"
+	case errors.Is(err, domain.ErrTokenMalformed):
+		message = "Token is malformed"
+	case errors.Is(err, domain.ErrInvalidToken):
+		message = "Invalid token"
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "An unexpected error occurred",
+		})
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"error":   "Unauthorized",
+		"message": message,
+	})
+}
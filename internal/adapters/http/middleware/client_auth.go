@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireResourceServer exige credenciales de un resource server registrado,
+// por HTTP Basic (client_secret_basic) o en el formulario (client_secret_post).
+func RequireResourceServer(registry ports.ResourceServerRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID, clientSecret, ok := c.Request.BasicAuth()
+		if !ok {
+			clientID = c.PostForm("client_id")
+			clientSecret = c.PostForm("client_secret")
+		}
+
+		if clientID == "" || registry.Authenticate(clientID, clientSecret) != nil {
+			c.Header("WWW-Authenticate", `Basic realm="introspection"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Invalid client credentials",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserAgentLength evita guardar cabeceras User-Agent desmesuradas
+const maxUserAgentLength = 512
+
+// ClientInfo guarda la IP y el User-Agent de la petición en su contexto para que los servicios
+// puedan asociarlos a las sesiones sin depender de HTTP
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userAgent := c.Request.UserAgent()
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+
+		ctx := domain.WithClientInfo(c.Request.Context(), domain.ClientInfo{
+			IP:        c.ClientIP(),
+			UserAgent: userAgent,
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
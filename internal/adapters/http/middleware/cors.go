@@ -15,8 +15,8 @@ func CORS() gin.HandlerFunc {
 	// Permitir los métodos comunes
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 	// Permitir las cabeceras comunes
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "Accept"}
-	config.ExposeHeaders = []string{"Content-Length"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "Accept", RequestIDHeader}
+	config.ExposeHeaders = []string{"Content-Length", RequestIDHeader}
 	config.AllowCredentials = true
 	config.MaxAge = 12 * time.Hour
 
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// maxRateLimitBodySize limita lo que se lee del body para extraer el identificador de cuenta
+const maxRateLimitBodySize = 64 << 10
+
+// accountFields son los campos del body que identifican la cuenta sobre la que se actúa
+var accountFields = []string{"email_or_nick_name", "email", "phone_number"}
+
+// RateLimitConfig contiene los límites aplicados a cada ruta; un límite sin configurar no se aplica
+type RateLimitConfig struct {
+	// PerIP limita las peticiones de cada IP a la ruta
+	PerIP domain.RateLimit
+	// PerAccount limita las peticiones a la ruta sobre una misma cuenta (email, nick o teléfono del body)
+	PerAccount domain.RateLimit
+	// PerRoute limita el total de peticiones a la ruta
+	PerRoute domain.RateLimit
+}
+
+type rateLimitCheck struct {
+	key   string
+	limit domain.RateLimit
+}
+
+// RateLimit aplica los límites de token bucket por IP, por cuenta y por ruta.
+// Las respuestas llevan las cabeceras RateLimit-* del límite más restrictivo y, al superarlo, 429 con Retry-After.
+// Si el almacén falla la petición se deja pasar para no convertir el rate limiting en un punto de caída.
+func RateLimit(limiter ports.RateLimiter, config RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		var checks []rateLimitCheck
+		if config.PerIP.Enabled() {
+			checks = append(checks, rateLimitCheck{key: "ip:" + route + ":" + c.ClientIP(), limit: config.PerIP})
+		}
+		if config.PerAccount.Enabled() {
+			if account := accountIdentifier(c); account != "" {
+				checks = append(checks, rateLimitCheck{key: "account:" + route + ":" + account, limit: config.PerAccount})
+			}
+		}
+		if config.PerRoute.Enabled() {
+			checks = append(checks, rateLimitCheck{key: "route:" + route, limit: config.PerRoute})
+		}
+
+		var tightest *domain.RateLimitResult
+		for _, check := range checks {
+			result, err := limiter.Allow(c.Request.Context(), check.key, check.limit)
+			if err != nil {
+				log.Printf("rate limiter unavailable, allowing request: %v", err)
+				continue
+			}
+			if tightest == nil || isMoreRestrictive(result, tightest) {
+				tightest = result
+			}
+			// Los buckets más amplios no se consumen con peticiones que ya se van a rechazar
+			if !result.Allowed {
+				break
+			}
+		}
+
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(tightest.ResetAfter))
+
+		if !tightest.Allowed {
+			c.Header("Retry-After", ceilSeconds(tightest.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too Many Requests",
+				"message": "Rate limit exceeded, try again later",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// accountIdentifier retorna el usuario autenticado o, en los endpoints públicos, extrae del body JSON el email,
+// nick o teléfono de la cuenta, hasheado para no guardarlo en claro. El body se restaura para que el handler pueda leerlo.
+func accountIdentifier(c *gin.Context) string {
+	if claims, ok := ClaimsFromContext(c); ok {
+		return claims.UserID
+	}
+
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRateLimitBodySize))
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+
+	for _, name := range accountFields {
+		if value, ok := fields[name].(string); ok {
+			// Un mismo teléfono escrito con otros separadores no debe estrenar bucket
+			if phoneNumber, err := domain.NormalizePhoneNumber(value); name == "phone_number" && err == nil {
+				value = phoneNumber
+			}
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				sum := sha256.Sum256([]byte(value))
+				return hex.EncodeToString(sum[:])
+			}
+		}
+	}
+	return ""
+}
+
+// isMoreRestrictive prioriza los límites superados y, entre los admitidos, el que deja menos margen
+func isMoreRestrictive(a, b *domain.RateLimitResult) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
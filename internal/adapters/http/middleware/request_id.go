@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader es la cabecera con la que se propaga el identificador de la petición
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern acepta los identificadores habituales de proxies y gateways sin admitir texto arbitrario
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID reutiliza el X-Request-ID recibido o genera uno nuevo, lo devuelve en la respuesta
+// y lo guarda en el contexto para correlacionar los eventos de auditoría de la petición
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(domain.WithCorrelationID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type passkeyHandler struct {
+	passkeyService ports.PasskeyService
+}
+
+// NewPasskeyHandler crea una nueva instancia del handler de passkeys
+func NewPasskeyHandler(passkeyService ports.PasskeyService) ports.PasskeyHandler {
+	return &passkeyHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+// List godoc
+// @Summary      Listar passkeys
+// @Description  Lista las passkeys registradas en la cuenta autenticada
+// @Tags         passkeys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.PasskeysResponse "Passkeys registradas"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /passkeys [get]
+func (h *passkeyHandler) List(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	passkeys, err := h.passkeyService.List(c.Request.Context(), claims.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, passkeys)
+}
+
+// BeginRegistration godoc
+// @Summary      Iniciar el registro de una passkey
+// @Description  Retorna las opciones para navigator.credentials.create(). La respuesta del autenticador se envía a /passkeys/register/finish junto con session_id
+// @Tags         passkeys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.WebAuthnOptionsResponse "Opciones de la ceremonia"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /passkeys/register/begin [post]
+func (h *passkeyHandler) BeginRegistration(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	options, err := h.passkeyService.BeginRegistration(c.Request.Context(), claims.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishRegistration godoc
+// @Summary      Completar el registro de una passkey
+// @Description  Verifica la respuesta del autenticador y guarda la passkey en la cuenta autenticada
+// @Tags         passkeys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.PasskeyRegistrationRequest true "Sesión, nombre y credencial creada"
+// @Success      201 {object} domain.PasskeyInfo "Passkey registrada"
+// @Failure      400 {object} ErrorResponse "Request inválido o ceremonia expirada"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      409 {object} ErrorResponse "La passkey ya está registrada"
+// @Failure      422 {object} ErrorResponse "La credencial no se pudo verificar"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /passkeys/register/finish [post]
+func (h *passkeyHandler) FinishRegistration(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	var req domain.PasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, passkey)
+}
+
+// Delete godoc
+// @Summary      Eliminar una passkey
+// @Description  Elimina una passkey de la cuenta autenticada
+// @Tags         passkeys
+// @Security     BearerAuth
+// @Param        id path string true "ID de la passkey"
+// @Success      204 "Passkey eliminada"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      404 {object} ErrorResponse "La passkey no existe"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /passkeys/{id} [delete]
+func (h *passkeyHandler) Delete(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.passkeyService.Delete(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type passwordHandler struct {
+	passwordService ports.PasswordService
+}
+
+// NewPasswordHandler crea una nueva instancia del handler de contraseñas
+func NewPasswordHandler(passwordService ports.PasswordService) ports.PasswordHandler {
+	return &passwordHandler{
+		passwordService: passwordService,
+	}
+}
+
+// ForgotPassword godoc
+// @Summary      Recuperar contraseña
+// @Description  Envía un enlace de un solo uso para restablecer la contraseña. La respuesta es siempre la misma exista o no la cuenta
+// @Tags         password
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.ForgotPasswordRequest true "Email de la cuenta"
+// @Success      202 {object} domain.MessageResponse "Solicitud aceptada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Router       /password/forgot [post]
+func (h *passwordHandler) ForgotPassword(c *gin.Context) {
+	var req domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, domain.MessageResponse{
+		Message: "If the account exists, a password reset email has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary      Restablecer contraseña
+// @Description  Aplica una nueva contraseña con el token del enlace de recuperación y cierra todas las sesiones del usuario
+// @Tags         password
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.ResetPasswordRequest true "Token y nueva contraseña"
+// @Success      200 {object} domain.MessageResponse "Contraseña restablecida"
+// @Failure      400 {object} ErrorResponse "Token inválido, expirado o ya usado"
+// @Failure      422 {object} ErrorResponse "La contraseña no cumple la política"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /password/reset [post]
+func (h *passwordHandler) ResetPassword(c *gin.Context) {
+	var req domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.MessageResponse{
+		Message: "Password has been reset",
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -9,25 +10,130 @@ import (
 )
 
 // SetupRouter configura las rutas de la aplicación
-func SetupRouter(authHandler ports.AuthHandler, healthHandler ports.HealthHandler) *gin.Engine {
+func SetupRouter(
+	authHandler ports.AuthHandler,
+	healthHandler ports.HealthHandler,
+	discoveryHandler ports.DiscoveryHandler,
+	adminHandler ports.AdminHandler,
+	identityHandler ports.IdentityHandler,
+	emailHandler ports.EmailHandler,
+	passwordHandler ports.PasswordHandler,
+	mfaHandler ports.MFAHandler,
+	passkeyHandler ports.PasskeyHandler,
+	sessionHandler ports.SessionHandler,
+	webhookHandler ports.WebhookHandler,
+	authService ports.AuthService,
+	resourceServers ports.ResourceServerRegistry,
+	rateLimiter ports.RateLimiter,
+	rateLimits middleware.RateLimitConfig,
+	trustedProxies []string,
+) (*gin.Engine, error) {
 	router := gin.Default()
 
+	// Solo se acepta X-Forwarded-For de los proxies configurados; sin ninguno,
+	// la IP del cliente es siempre la de la conexión y no se puede falsear
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
 	// Aplicar middlewares globales
+	router.Use(middleware.RequestID())
 	router.Use(middleware.CORS())
 	router.Use(middleware.SecurityHeaders())
+	router.Use(middleware.ClientInfo())
 
 	// Health check endpoint
 	router.GET("/health", healthHandler.Health)
 
+	// Endpoints públicos de descubrimiento
+	router.GET("/.well-known/jwks.json", discoveryHandler.JWKS)
+	router.GET("/.well-known/openid-configuration", discoveryHandler.OpenIDConfiguration)
+
+	// Rate limiting de los endpoints públicos expuestos a fuerza bruta
+	rateLimit := middleware.RateLimit(rateLimiter, rateLimits)
+
 	// API v1 routes
 	v1 := router.Group("/v1")
 	{
-		v1.POST("/login", authHandler.Login)
-		v1.POST("/login/oauth", authHandler.OauthLogin)
-		v1.POST("/validate", authHandler.Validate)
-		v1.POST("/refresh", authHandler.Refresh)
+		v1.POST("/login", rateLimit, authHandler.Login)
+		v1.POST("/login/mfa", rateLimit, authHandler.LoginMFA)
+		v1.POST("/login/mfa/webauthn", rateLimit, authHandler.BeginMFAPasskey)
+		v1.POST("/login/passkey/begin", rateLimit, authHandler.BeginPasskeyLogin)
+		v1.POST("/login/passkey/finish", rateLimit, authHandler.LoginPasskey)
+		v1.POST("/login/magic-link", rateLimit, authHandler.RequestMagicLink)
+		v1.GET("/login/magic-link/verify", rateLimit, authHandler.LoginMagicLink)
+		v1.POST("/login/magic-link/verify", rateLimit, authHandler.LoginMagicLink)
+		v1.POST("/login/sms", rateLimit, authHandler.RequestSMSCode)
+		v1.POST("/login/sms/verify", rateLimit, authHandler.LoginSMS)
+		v1.POST("/login/oauth", rateLimit, authHandler.OauthLogin)
+		v1.POST("/register", rateLimit, authHandler.Register)
+		v1.GET("/email/verify", rateLimit, emailHandler.VerifyEmail)
+		v1.POST("/email/verify", rateLimit, emailHandler.VerifyEmail)
+		v1.POST("/email/resend", rateLimit, emailHandler.ResendVerification)
+		v1.POST("/password/forgot", rateLimit, passwordHandler.ForgotPassword)
+		v1.POST("/password/reset", rateLimit, passwordHandler.ResetPassword)
+		v1.POST("/validate", rateLimit, authHandler.Validate)
+		v1.POST("/refresh", rateLimit, authHandler.Refresh)
+		v1.POST("/logout", middleware.RequireAuth(authService), authHandler.Logout)
+		v1.POST("/logout/all", middleware.RequireAuth(authService), authHandler.LogoutAll)
+		v1.POST("/password/change", middleware.RequireAuth(authService), authHandler.ChangePassword)
+		v1.POST("/password/set", middleware.RequireAuth(authService), authHandler.SetPassword)
+		v1.GET("/userinfo", middleware.RequireAuth(authService), authHandler.UserInfo)
+		v1.POST("/userinfo", middleware.RequireAuth(authService), authHandler.UserInfo)
+		v1.POST("/introspect", middleware.RequireResourceServer(resourceServers), authHandler.Introspect)
 		v1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
-	return router
+	// Proveedores externos vinculados a la cuenta autenticada
+	identities := v1.Group("/identities", middleware.RequireAuth(authService), rateLimit)
+	{
+		identities.GET("", identityHandler.ListIdentities)
+		identities.POST("", identityHandler.LinkIdentity)
+		identities.DELETE("/:provider", identityHandler.UnlinkIdentity)
+	}
+
+	// Segundo factor de la cuenta autenticada
+	// Las operaciones que comprueban un código se limitan también por usuario,
+	// igual que el resto de grupos de la cuenta autenticada
+	mfa := v1.Group("/mfa", middleware.RequireAuth(authService), rateLimit)
+	{
+		mfa.GET("", mfaHandler.Status)
+		mfa.POST("/totp/enroll", mfaHandler.EnrollTOTP)
+		mfa.POST("/totp/confirm", mfaHandler.ConfirmTOTP)
+		mfa.POST("/disable", mfaHandler.Disable)
+		mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+	}
+
+	// Passkeys de la cuenta autenticada
+	passkeys := v1.Group("/passkeys", middleware.RequireAuth(authService), rateLimit)
+	{
+		passkeys.GET("", passkeyHandler.List)
+		passkeys.POST("/register/begin", passkeyHandler.BeginRegistration)
+		passkeys.POST("/register/finish", passkeyHandler.FinishRegistration)
+		passkeys.DELETE("/:id", passkeyHandler.Delete)
+	}
+
+	// Sesiones de la cuenta autenticada en sus dispositivos
+	sessions := v1.Group("/sessions", middleware.RequireAuth(authService), rateLimit)
+	{
+		sessions.GET("", sessionHandler.List)
+		sessions.DELETE("/:id", sessionHandler.Revoke)
+	}
+
+	// Endpoints de administración, solo para el rol admin
+	admin := v1.Group("/admin", middleware.RequireAuth(authService), middleware.RequireRole(domain.RoleAdmin))
+	{
+		admin.GET("/keys", adminHandler.ListSigningKeys)
+		admin.POST("/keys/rotate", adminHandler.RotateSigningKey)
+		admin.POST("/users/:id/unlock", adminHandler.UnlockUser)
+		admin.POST("/users/:id/deactivate", adminHandler.DeactivateUser)
+		admin.GET("/audit/events", adminHandler.ListAuthEvents)
+		admin.POST("/webhooks", webhookHandler.CreateSubscription)
+		admin.GET("/webhooks", webhookHandler.ListSubscriptions)
+		admin.DELETE("/webhooks/:id", webhookHandler.DeleteSubscription)
+		admin.GET("/webhooks/deliveries", webhookHandler.ListDeliveries)
+		admin.POST("/webhooks/deliveries/:id/replay", webhookHandler.ReplayDelivery)
+	}
+
+	return router, nil
 }
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/adapters/http/middleware"
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type sessionHandler struct {
+	sessionService ports.SessionService
+}
+
+// NewSessionHandler crea una nueva instancia del handler de sesiones
+func NewSessionHandler(sessionService ports.SessionService) ports.SessionHandler {
+	return &sessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// List godoc
+// @Summary      Listar sesiones
+// @Description  Lista las sesiones activas de la cuenta autenticada con el dispositivo, la IP y los métodos de autenticación de cada una. La sesión del token usado se marca con current
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SessionsResponse "Sesiones activas"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /sessions [get]
+func (h *sessionHandler) List(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	sessions, err := h.sessionService.List(c.Request.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// Revoke godoc
+// @Summary      Cerrar una sesión
+// @Description  Cierra una sesión de la cuenta autenticada: sus access tokens dejan de ser válidos y sus refresh tokens se revocan
+// @Tags         sessions
+// @Security     BearerAuth
+// @Param        id path string true "ID de la sesión (claim sid)"
+// @Success      204 "Sesión cerrada"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      404 {object} ErrorResponse "La sesión no existe o ya está cerrada"
+// @Failure      429 {object} ErrorResponse "Demasiadas peticiones (ver cabeceras RateLimit-* y Retry-After)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /sessions/{id} [delete]
+func (h *sessionHandler) Revoke(c *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		handleError(c, domain.ErrUnauthorized)
+		return
+	}
+
+	if err := h.sessionService.Revoke(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
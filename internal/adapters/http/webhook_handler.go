@@ -0,0 +1,155 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/gin-gonic/gin"
+)
+
+type webhookHandler struct {
+	webhookService ports.WebhookService
+}
+
+// NewWebhookHandler crea una nueva instancia del handler de webhooks
+func NewWebhookHandler(webhookService ports.WebhookService) ports.WebhookHandler {
+	return &webhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateSubscription godoc
+// @Summary      Crear una suscripción de webhooks
+// @Description  Registra un endpoint que recibirá los eventos de seguridad indicados. La respuesta incluye el secreto de firma, que no se vuelve a mostrar
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateWebhookRequest true "Endpoint y eventos"
+// @Success      201 {object} domain.WebhookSubscriptionCreated "Suscripción creada"
+// @Failure      400 {object} ErrorResponse "Request inválido"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      422 {object} ErrorResponse "URL o tipos de evento inválidos"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/webhooks [post]
+func (h *webhookHandler) CreateSubscription(c *gin.Context) {
+	var req domain.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListSubscriptions godoc
+// @Summary      Listar suscripciones de webhooks
+// @Description  Lista las suscripciones registradas, sin sus secretos de firma
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.WebhookSubscriptionsResponse "Suscripciones"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/webhooks [get]
+func (h *webhookHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.webhookService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// DeleteSubscription godoc
+// @Summary      Eliminar una suscripción de webhooks
+// @Description  Elimina la suscripción y descarta sus entregas, incluidas las pendientes
+// @Tags         webhooks
+// @Security     BearerAuth
+// @Param        id path string true "ID de la suscripción"
+// @Success      204 "Suscripción eliminada"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      404 {object} ErrorResponse "Suscripción no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/webhooks/{id} [delete]
+func (h *webhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+// @Summary      Listar entregas de webhooks
+// @Description  Lista las entregas más recientes del outbox con su estado, intentos y último error. Todos los filtros son opcionales
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status query string false "Estado de la entrega" Enums(pending, delivered, dead)
+// @Param        subscription_id query string false "ID de la suscripción"
+// @Param        limit query int false "Número de entregas (1-200, 50 por defecto)"
+// @Success      200 {object} domain.WebhookDeliveriesResponse "Entregas"
+// @Failure      400 {object} ErrorResponse "Parámetros mal formados"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      422 {object} ErrorResponse "Filtros inválidos"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/webhooks/deliveries [get]
+func (h *webhookHandler) ListDeliveries(c *gin.Context) {
+	var query domain.WebhookDeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), query)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery godoc
+// @Summary      Reenviar una entrega de webhook
+// @Description  Vuelve a poner en cola la entrega con los intentos a cero, normalmente una que agotó sus reintentos (dead). Se reenvía el mismo evento con el mismo X-Webhook-Id
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "ID de la entrega"
+// @Success      202 {object} domain.MessageResponse "Entrega en cola"
+// @Failure      401 {object} ErrorResponse "Token inválido o expirado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes"
+// @Failure      404 {object} ErrorResponse "Entrega no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor"
+// @Router       /admin/webhooks/deliveries/{id}/replay [post]
+func (h *webhookHandler) ReplayDelivery(c *gin.Context) {
+	if err := h.webhookService.Replay(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, domain.MessageResponse{
+		Message: "Webhook delivery queued",
+	})
+}
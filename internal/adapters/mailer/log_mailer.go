@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// logMailer implementa Mailer para desarrollo local: escribe los emails en el log o en ficheros
+type logMailer struct {
+	dir string
+}
+
+// NewLogMailer crea un Mailer que no envía nada. Si dir no está vacío cada email se guarda
+// como fichero .eml en ese directorio; si no, se escribe en el log
+func NewLogMailer(dir string) ports.Mailer {
+	return &logMailer{dir: dir}
+}
+
+// Send escribe el email en el log o en un fichero
+func (m *logMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	if m.dir == "" {
+		log.Printf("mail to=%s subject=%q\n%s", message.To, message.Subject, message.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), sanitizeFileName(message.To))
+	if err := os.WriteFile(filepath.Join(m.dir, name), buildMessage("", message), 0o600); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
+
+// sanitizeFileName reemplaza los caracteres que no son seguros en un nombre de fichero
+func sanitizeFileName(value string) string {
+	out := []rune(value)
+	for i, r := range out {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '@' || r == '.' || r == '-' || r == '_') {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// SMTPConfig contiene la configuración del servidor SMTP
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// smtpMailer implementa Mailer enviando los emails por SMTP (con STARTTLS si el servidor lo ofrece)
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer crea un Mailer que envía los emails por SMTP
+func NewSMTPMailer(config SMTPConfig) ports.Mailer {
+	return &smtpMailer{config: config}
+}
+
+// Send envía el email por SMTP
+func (m *smtpMailer) Send(ctx context.Context, message domain.EmailMessage) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{message.To}, buildMessage(m.config.From, message)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// buildMessage construye el mensaje RFC 5322 en texto plano
+func buildMessage(from string, message domain.EmailMessage) []byte {
+	var buf bytes.Buffer
+	if from != "" {
+		fmt.Fprintf(&buf, "From: %s\r\n", from)
+	}
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
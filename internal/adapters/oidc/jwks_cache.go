@@ -0,0 +1,131 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+const (
+	// defaultJWKSMaxAge se usa cuando el proveedor no indica Cache-Control max-age
+	defaultJWKSMaxAge = time.Hour
+	// minJWKSRefreshInterval limita las descargas forzadas por kids desconocidos
+	minJWKSRefreshInterval = time.Minute
+)
+
+// errKeyNotFound indica que el JWKS del proveedor no contiene el kid del token
+var errKeyNotFound = errors.New("signing key not found in provider JWKS")
+
+// jwksCache mantiene en memoria las claves públicas de un proveedor
+type jwksCache struct {
+	url    string
+	client *http.Client
+	// now es el reloj de la caché; los tests lo reemplazan para simular el paso del tiempo
+	now func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	expiresAt   time.Time
+	lastFetchAt time.Time
+}
+
+func newJWKSCache(url string, client *http.Client) *jwksCache {
+	return &jwksCache{
+		url:    url,
+		client: client,
+		now:    time.Now,
+		keys:   make(map[string]crypto.PublicKey),
+	}
+}
+
+// key retorna la clave pública con el kid indicado.
+// Descarga de nuevo el JWKS si la caché expiró o si el kid es desconocido (el proveedor pudo rotar sus claves).
+func (c *jwksCache) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if key, ok := c.keys[kid]; ok && now.Before(c.expiresAt) {
+		return key, nil
+	}
+
+	if now.Before(c.expiresAt) && now.Sub(c.lastFetchAt) < minJWKSRefreshInterval {
+		return nil, errKeyNotFound
+	}
+
+	if err := c.refresh(ctx, now); err != nil {
+		return nil, err
+	}
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, errKeyNotFound
+	}
+	return key, nil
+}
+
+// refresh descarga el JWKS del proveedor y reemplaza las claves en caché
+func (c *jwksCache) refresh(ctx context.Context, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build JWKS request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var jwks domain.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			// Una clave no soportada no debe invalidar el resto del JWKS
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	c.keys = keys
+	c.lastFetchAt = now
+	c.expiresAt = now.Add(cacheMaxAge(resp.Header.Get("Cache-Control")))
+	return nil
+}
+
+// cacheMaxAge extrae la directiva max-age de la cabecera Cache-Control
+func cacheMaxAge(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			break
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultJWKSMaxAge
+}
@@ -0,0 +1,152 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultLeeway      = 30 * time.Second
+	defaultHTTPTimeout = 10 * time.Second
+)
+
+// ProviderConfig contiene la configuración de un proveedor de identidad
+type ProviderConfig struct {
+	Name      string
+	JWKSURL   string
+	Issuers   []string
+	ClientIDs []string
+}
+
+// Config contiene la configuración del verificador de ID tokens
+type Config struct {
+	Providers []ProviderConfig
+	// HTTPClient permite inyectar el cliente usado para descargar los JWKS (p. ej. contra un servidor de pruebas)
+	HTTPClient *http.Client
+	// Leeway es la tolerancia de reloj al validar exp, iat y nbf
+	Leeway time.Duration
+}
+
+type provider struct {
+	config ProviderConfig
+	jwks   *jwksCache
+}
+
+// identityVerifier implementa IdentityVerifier validando ID tokens OIDC contra el JWKS de cada proveedor
+type identityVerifier struct {
+	providers map[string]*provider
+	leeway    time.Duration
+}
+
+// idTokenClaims representa los claims de un ID token de OpenID Connect
+type idTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	jwt.RegisteredClaims
+}
+
+// NewIdentityVerifier crea un nuevo verificador de ID tokens
+func NewIdentityVerifier(cfg Config) ports.IdentityVerifier {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	leeway := cfg.Leeway
+	if leeway == 0 {
+		leeway = defaultLeeway
+	}
+
+	providers := make(map[string]*provider, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		// Sin client IDs no hay audiencia que aceptar
+		if len(p.ClientIDs) == 0 {
+			continue
+		}
+		providers[p.Name] = &provider{
+			config: p,
+			jwks:   newJWKSCache(p.JWKSURL, client),
+		}
+	}
+
+	return &identityVerifier{
+		providers: providers,
+		leeway:    leeway,
+	}
+}
+
+// Verify valida el ID token y retorna la identidad externa que contiene
+func (v *identityVerifier) Verify(ctx context.Context, providerName, idToken string) (*domain.ExternalIdentity, error) {
+	p, ok := v.providers[providerName]
+	if !ok {
+		return nil, domain.ErrUnsupportedProvider
+	}
+
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errKeyNotFound
+		}
+		return p.jwks.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		// Si el JWKS no se pudo descargar el problema es nuestro, no del token
+		if !errors.Is(err, errKeyNotFound) && errors.Is(err, jwt.ErrTokenUnverifiable) {
+			return nil, fmt.Errorf("failed to verify id token: %w", err)
+		}
+		return nil, domain.ErrInvalidIDToken
+	}
+
+	if !slices.Contains(p.config.Issuers, claims.Issuer) {
+		return nil, domain.ErrInvalidIDToken
+	}
+
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool {
+		return slices.Contains(p.config.ClientIDs, aud)
+	}) {
+		return nil, domain.ErrInvalidIDToken
+	}
+
+	if claims.Subject == "" {
+		return nil, domain.ErrInvalidIDToken
+	}
+
+	return &domain.ExternalIdentity{
+		Provider:      providerName,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: parseEmailVerified(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// parseEmailVerified interpreta email_verified, que algunos proveedores envían como string
+func parseEmailVerified(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		verified, _ := strconv.ParseBool(v)
+		return verified
+	default:
+		return false
+	}
+}
@@ -0,0 +1,410 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testProvider = "google"
+	testIssuer   = "https://accounts.google.com"
+	testClientID = "web-client.apps.googleusercontent.com"
+)
+
+func TestVerifyAcceptsValidIDToken(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	key := jwks.addRSAKey(t, "rsa-1")
+	verifier := jwks.verifier()
+
+	identity, err := verifier.Verify(context.Background(), testProvider, signToken(t, key, validClaims()))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	want := domain.ExternalIdentity{
+		Provider:      testProvider,
+		Subject:       "110169484474386276334",
+		Email:         "ana@example.com",
+		EmailVerified: true,
+		GivenName:     "Ana",
+		FamilyName:    "García",
+	}
+	if *identity != want {
+		t.Fatalf("identity = %+v, want %+v", *identity, want)
+	}
+}
+
+func TestVerifyAcceptsES256(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	key := jwks.addECKey(t, "ec-1")
+
+	if _, err := jwks.verifier().Verify(context.Background(), testProvider, signToken(t, key, validClaims())); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestVerifyRejectsInvalidIDTokens(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	key := jwks.addRSAKey(t, "rsa-1")
+
+	// Clave del atacante con el kid de la clave publicada: la firma no valida
+	forged := newSigningKey(t, "rsa-1")
+
+	tests := []struct {
+		name   string
+		key    signingKey
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "signature from another key", key: forged},
+		{name: "unknown issuer", key: key, modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "audience of another client", key: key, modify: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "expired", key: key, modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() }},
+		{name: "without exp", key: key, modify: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "issued in the future", key: key, modify: func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{name: "without subject", key: key, modify: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "unknown kid", key: signingKey{kid: "missing", private: key.private}},
+		{name: "without kid", key: signingKey{private: key.private}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+
+			_, err := jwks.verifier().Verify(context.Background(), testProvider, signToken(t, tt.key, claims))
+			if !errors.Is(err, domain.ErrInvalidIDToken) {
+				t.Fatalf("error = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsSymmetricAlgorithms(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	jwks.addRSAKey(t, "rsa-1")
+
+	// HS256 firmado con un secreto cualquiera no debe aceptarse nunca
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+	token.Header["kid"] = "rsa-1"
+	signed, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	if _, err := jwks.verifier().Verify(context.Background(), testProvider, signed); !errors.Is(err, domain.ErrInvalidIDToken) {
+		t.Fatalf("error = %v, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestVerifyUnsupportedProvider(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	key := jwks.addRSAKey(t, "rsa-1")
+
+	_, err := jwks.verifier().Verify(context.Background(), "github", signToken(t, key, validClaims()))
+	if !errors.Is(err, domain.ErrUnsupportedProvider) {
+		t.Fatalf("error = %v, want ErrUnsupportedProvider", err)
+	}
+}
+
+func TestVerifyEmailVerified(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	key := jwks.addRSAKey(t, "rsa-1")
+	verifier := jwks.verifier()
+
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{name: "boolean true", value: true, want: true},
+		{name: "boolean false", value: false, want: false},
+		{name: "string true", value: "true", want: true},
+		{name: "string false", value: "false", want: false},
+		{name: "unparseable string", value: "yes", want: false},
+		{name: "absent", value: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.value == nil {
+				delete(claims, "email_verified")
+			} else {
+				claims["email_verified"] = tt.value
+			}
+
+			identity, err := verifier.Verify(context.Background(), testProvider, signToken(t, key, claims))
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if identity.EmailVerified != tt.want {
+				t.Fatalf("EmailVerified = %v, want %v", identity.EmailVerified, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyKeyRollover(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	oldKey := jwks.addRSAKey(t, "2025-01")
+	verifier := jwks.verifier()
+	clock := verifier.setClock(time.Now())
+	ctx := context.Background()
+
+	if _, err := verifier.Verify(ctx, testProvider, signToken(t, oldKey, validClaims())); err != nil {
+		t.Fatalf("Verify with the initial key: %v", err)
+	}
+	if got := jwks.fetchCount(); got != 1 {
+		t.Fatalf("JWKS fetches = %d, want 1", got)
+	}
+
+	// El proveedor publica una clave nueva y retira la anterior
+	jwks.removeKey("2025-01")
+	newKey := jwks.addRSAKey(t, "2025-02")
+
+	// Un kid desconocido justo después de descargar no fuerza otra descarga
+	clock.advance(10 * time.Second)
+	if _, err := verifier.Verify(ctx, testProvider, signToken(t, newKey, validClaims())); !errors.Is(err, domain.ErrInvalidIDToken) {
+		t.Fatalf("error within the refresh interval = %v, want ErrInvalidIDToken", err)
+	}
+	if got := jwks.fetchCount(); got != 1 {
+		t.Fatalf("JWKS fetches within the refresh interval = %d, want 1", got)
+	}
+
+	// Pasado el intervalo mínimo el kid desconocido descarga de nuevo el JWKS
+	clock.advance(minJWKSRefreshInterval)
+	if _, err := verifier.Verify(ctx, testProvider, signToken(t, newKey, validClaims())); err != nil {
+		t.Fatalf("Verify with the rotated key: %v", err)
+	}
+	if got := jwks.fetchCount(); got != 2 {
+		t.Fatalf("JWKS fetches after the rollover = %d, want 2", got)
+	}
+
+	// La clave retirada ya no está en la caché
+	if _, err := verifier.Verify(ctx, testProvider, signToken(t, oldKey, validClaims())); !errors.Is(err, domain.ErrInvalidIDToken) {
+		t.Fatalf("error with the retired key = %v, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestVerifyRefreshesExpiredCache(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	jwks.maxAge = "public, max-age=300, must-revalidate"
+	key := jwks.addRSAKey(t, "rsa-1")
+	verifier := jwks.verifier()
+	clock := verifier.setClock(time.Now())
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := verifier.Verify(ctx, testProvider, signToken(t, key, validClaims())); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+	}
+	if got := jwks.fetchCount(); got != 1 {
+		t.Fatalf("JWKS fetches while cached = %d, want 1", got)
+	}
+
+	clock.advance(301 * time.Second)
+	if _, err := verifier.Verify(ctx, testProvider, signToken(t, key, validClaims())); err != nil {
+		t.Fatalf("Verify after max-age: %v", err)
+	}
+	if got := jwks.fetchCount(); got != 2 {
+		t.Fatalf("JWKS fetches after max-age = %d, want 2", got)
+	}
+}
+
+func TestVerifyJWKSUnavailable(t *testing.T) {
+	jwks := newStubJWKSServer(t)
+	key := jwks.addRSAKey(t, "rsa-1")
+	jwks.status = http.StatusServiceUnavailable
+
+	// Un fallo al descargar las claves es un error interno, no un token inválido
+	_, err := jwks.verifier().Verify(context.Background(), testProvider, signToken(t, key, validClaims()))
+	if err == nil || errors.Is(err, domain.ErrInvalidIDToken) {
+		t.Fatalf("error = %v, want an internal error", err)
+	}
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            testIssuer,
+		"aud":            testClientID,
+		"sub":            "110169484474386276334",
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"email":          "ana@example.com",
+		"email_verified": true,
+		"given_name":     "Ana",
+		"family_name":    "García",
+	}
+}
+
+// signingKey es una clave privada del proveedor con su kid
+type signingKey struct {
+	kid     string
+	private crypto.Signer
+}
+
+func newSigningKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return signingKey{kid: kid, private: private}
+}
+
+func signToken(t *testing.T, key signingKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	method := jwt.SigningMethod(jwt.SigningMethodRS256)
+	if _, ok := key.private.(*ecdsa.PrivateKey); ok {
+		method = jwt.SigningMethodES256
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if key.kid != "" {
+		token.Header["kid"] = key.kid
+	}
+	signed, err := token.SignedString(key.private)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// stubJWKSServer publica un JWKS que los tests pueden rotar y cuenta las descargas
+type stubJWKSServer struct {
+	server *httptest.Server
+	status int
+	maxAge string
+
+	mu      sync.Mutex
+	keys    []domain.JWK
+	fetches int
+}
+
+func newStubJWKSServer(t *testing.T) *stubJWKSServer {
+	t.Helper()
+
+	s := &stubJWKSServer{status: http.StatusOK}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetches++
+
+		if s.status != http.StatusOK {
+			w.WriteHeader(s.status)
+			return
+		}
+		if s.maxAge != "" {
+			w.Header().Set("Cache-Control", s.maxAge)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(domain.JWKS{Keys: s.keys})
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *stubJWKSServer) addRSAKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	key := newSigningKey(t, kid)
+	s.publish(t, kid, "RS256", key.private.Public())
+	return key
+}
+
+func (s *stubJWKSServer) addECKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	s.publish(t, kid, "ES256", private.Public())
+	return signingKey{kid: kid, private: private}
+}
+
+func (s *stubJWKSServer) publish(t *testing.T, kid, alg string, public crypto.PublicKey) {
+	t.Helper()
+	jwk, err := domain.NewJWK(kid, alg, public)
+	if err != nil {
+		t.Fatalf("build jwk: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, jwk)
+}
+
+func (s *stubJWKSServer) removeKey(kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.keys[:0]
+	for _, key := range s.keys {
+		if key.Kid != kid {
+			keys = append(keys, key)
+		}
+	}
+	s.keys = keys
+}
+
+func (s *stubJWKSServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func (s *stubJWKSServer) verifier() *testVerifier {
+	verifier := NewIdentityVerifier(Config{
+		Providers: []ProviderConfig{{
+			Name:      testProvider,
+			JWKSURL:   s.server.URL,
+			Issuers:   []string{testIssuer, "accounts.google.com"},
+			ClientIDs: []string{testClientID},
+		}},
+		HTTPClient: s.server.Client(),
+	})
+	return &testVerifier{identityVerifier: verifier.(*identityVerifier)}
+}
+
+type testVerifier struct {
+	*identityVerifier
+}
+
+// setClock sustituye el reloj de la caché JWKS del proveedor por uno controlado por el test
+func (v *testVerifier) setClock(start time.Time) *fakeClock {
+	clock := &fakeClock{now: start}
+	v.providers[testProvider].jwks.now = clock.Now
+	return clock
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type authEventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuthEventRepository(pool *pgxpool.Pool) ports.AuthEventRepository {
+	return &authEventRepository{pool: pool}
+}
+
+func (r *authEventRepository) CreateBatch(ctx context.Context, events []domain.AuthEvent) error {
+	query := `
+		INSERT INTO auth_events (
+			type, user_id, identifier, ip, user_agent, outcome, reason, correlation_id, metadata, occurred_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		var metadata []byte
+		if len(event.Metadata) > 0 {
+			encoded, err := json.Marshal(event.Metadata)
+			if err != nil {
+				return fmt.Errorf("failed to encode auth event metadata: %w", err)
+			}
+			metadata = encoded
+		}
+		batch.Queue(query,
+			event.Type, event.UserID, event.Identifier, event.IP, event.UserAgent, event.Outcome,
+			event.Reason, event.CorrelationID, metadata, event.OccurredAt,
+		)
+	}
+
+	if err := r.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to create auth events: %w", err)
+	}
+	return nil
+}
+
+func (r *authEventRepository) List(ctx context.Context, filter domain.AuthEventFilter) ([]*domain.AuthEvent, error) {
+	var conditions []string
+	var args []any
+	where := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Type != "" {
+		where("type = $%d", filter.Type)
+	}
+	if filter.UserID != "" {
+		where("user_id = $%d", filter.UserID)
+	}
+	if filter.Identifier != "" {
+		where("identifier = $%d", filter.Identifier)
+	}
+	if filter.IP != "" {
+		where("ip = $%d", filter.IP)
+	}
+	if filter.Outcome != "" {
+		where("outcome = $%d", filter.Outcome)
+	}
+	if filter.CorrelationID != "" {
+		where("correlation_id = $%d", filter.CorrelationID)
+	}
+	if !filter.From.IsZero() {
+		where("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("occurred_at < $%d", filter.To)
+	}
+	if filter.BeforeID > 0 {
+		where("id < $%d", filter.BeforeID)
+	}
+
+	query := `
+		SELECT id, type, user_id, identifier, ip, user_agent, outcome, reason, correlation_id, metadata, occurred_at
+		FROM auth_events
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auth events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*domain.AuthEvent, 0)
+	for rows.Next() {
+		event := &domain.AuthEvent{}
+		var metadata []byte
+		if err := rows.Scan(
+			&event.ID, &event.Type, &event.UserID, &event.Identifier, &event.IP, &event.UserAgent,
+			&event.Outcome, &event.Reason, &event.CorrelationID, &metadata, &event.OccurredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auth event: %w", err)
+		}
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to decode auth event metadata: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list auth events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type loginAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool) ports.LoginAttemptRepository {
+	return &loginAttemptRepository{pool: pool}
+}
+
+func (r *loginAttemptRepository) Get(ctx context.Context, userID string) (*domain.LoginAttempts, error) {
+	query := `SELECT user_id, failed_count, last_failed_at, locked_until FROM login_attempts WHERE user_id = $1`
+	attempts := &domain.LoginAttempts{}
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&attempts.UserID, &attempts.FailedCount, &attempts.LastFailedAt, &attempts.LockedUntil,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, userID string, resetBefore time.Time) (*domain.LoginAttempts, error) {
+	// A single upsert keeps the counter consistent across replicas
+	query := `
+		INSERT INTO login_attempts (user_id, failed_count, last_failed_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			failed_count = CASE WHEN login_attempts.last_failed_at < $2 THEN 1 ELSE login_attempts.failed_count + 1 END,
+			last_failed_at = NOW()
+		RETURNING user_id, failed_count, last_failed_at, locked_until
+	`
+	attempts := &domain.LoginAttempts{}
+	err := r.pool.QueryRow(ctx, query, userID, resetBefore).Scan(
+		&attempts.UserID, &attempts.FailedCount, &attempts.LastFailedAt, &attempts.LockedUntil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return attempts, nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, userID string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = GREATEST(COALESCE(locked_until, $2), $2) WHERE user_id = $1`
+	if _, err := r.pool.Exec(ctx, query, userID, until); err != nil {
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) Reset(ctx context.Context, userID string) error {
+	query := `DELETE FROM login_attempts WHERE user_id = $1`
+	if _, err := r.pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type mfaRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMFARepository(pool *pgxpool.Pool) ports.MFARepository {
+	return &mfaRepository{pool: pool}
+}
+
+func (r *mfaRepository) SavePending(ctx context.Context, factor *domain.MFAFactor) error {
+	// The conditional upsert never overwrites an enabled factor
+	query := `
+		INSERT INTO user_mfa (user_id, encrypted_secret, enabled, recovery_code_hashes, last_used_step, created_at)
+		VALUES ($1, $2, false, '{}', 0, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			encrypted_secret = EXCLUDED.encrypted_secret,
+			recovery_code_hashes = '{}',
+			last_used_step = 0,
+			created_at = EXCLUDED.created_at
+		WHERE user_mfa.enabled = false
+	`
+	result, err := r.pool.Exec(ctx, query, factor.UserID, factor.EncryptedSecret, factor.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save mfa enrollment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *mfaRepository) Get(ctx context.Context, userID string) (*domain.MFAFactor, error) {
+	query := `
+		SELECT user_id, encrypted_secret, enabled, recovery_code_hashes, last_used_step, created_at, confirmed_at
+		FROM user_mfa WHERE user_id = $1
+	`
+	factor := &domain.MFAFactor{}
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&factor.UserID, &factor.EncryptedSecret, &factor.Enabled, &factor.RecoveryCodeHashes,
+		&factor.LastUsedStep, &factor.CreatedAt, &factor.ConfirmedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrMFANotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get mfa factor: %w", err)
+	}
+	return factor, nil
+}
+
+func (r *mfaRepository) Enable(ctx context.Context, userID string, recoveryCodeHashes []string, step int64) error {
+	query := `
+		UPDATE user_mfa SET enabled = true, confirmed_at = NOW(), recovery_code_hashes = $2, last_used_step = $3
+		WHERE user_id = $1 AND enabled = false
+	`
+	result, err := r.pool.Exec(ctx, query, userID, recoveryCodeHashes, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable mfa: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *mfaRepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := `UPDATE user_mfa SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+	result, err := r.pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record totp step: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *mfaRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := `
+		UPDATE user_mfa SET recovery_code_hashes = array_remove(recovery_code_hashes, $2)
+		WHERE user_id = $1 AND enabled = true AND $2 = ANY(recovery_code_hashes)
+	`
+	result, err := r.pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, recoveryCodeHashes []string) error {
+	query := `UPDATE user_mfa SET recovery_code_hashes = $2 WHERE user_id = $1 AND enabled = true`
+	result, err := r.pool.Exec(ctx, query, userID, recoveryCodeHashes)
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrMFANotEnabled
+	}
+	return nil
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID string) error {
+	query := `DELETE FROM user_mfa WHERE user_id = $1`
+	if _, err := r.pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to delete mfa factor: %w", err)
+	}
+	return nil
+}
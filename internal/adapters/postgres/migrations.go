@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/bikes2road/authentication/internal/domain"
@@ -29,9 +30,197 @@ func RunMigrations(pool *pgxpool.Pool) error {
 		date_updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
 	);
 
-	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
-	CREATE INDEX IF NOT EXISTS idx_users_nick_name ON users(nick_name);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT false;
+
 	CREATE INDEX IF NOT EXISTS idx_users_date_created ON users(date_created);
+
+	CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id UUID PRIMARY KEY,
+		family_id UUID NOT NULL,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		token_hash VARCHAR(64) NOT NULL UNIQUE,
+		expires_at TIMESTAMPTZ NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		rotated_at TIMESTAMPTZ,
+		revoked_at TIMESTAMPTZ
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+
+	CREATE TABLE IF NOT EXISTS revoked_tokens (
+		jti VARCHAR(64) PRIMARY KEY,
+		user_id UUID NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL,
+		revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+	CREATE TABLE IF NOT EXISTS user_token_revocations (
+		user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		revoked_before TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS user_identities (
+		provider VARCHAR(50) NOT NULL,
+		provider_subject VARCHAR(255) NOT NULL,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		email_at_link VARCHAR(255) NOT NULL,
+		linked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (provider, provider_subject),
+		UNIQUE (user_id, provider)
+	);
+
+	CREATE TABLE IF NOT EXISTS one_time_tokens (
+		id UUID PRIMARY KEY,
+		purpose VARCHAR(50) NOT NULL,
+		token_hash VARCHAR(64) NOT NULL UNIQUE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		expires_at TIMESTAMPTZ NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		consumed_at TIMESTAMPTZ
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_one_time_tokens_user_purpose ON one_time_tokens(user_id, purpose);
+	CREATE INDEX IF NOT EXISTS idx_one_time_tokens_expires_at ON one_time_tokens(expires_at);
+
+	CREATE TABLE IF NOT EXISTS login_attempts (
+		user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		failed_count INTEGER NOT NULL DEFAULT 0,
+		last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		locked_until TIMESTAMPTZ
+	);
+
+	CREATE TABLE IF NOT EXISTS user_mfa (
+		user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		encrypted_secret TEXT NOT NULL,
+		enabled BOOLEAN NOT NULL DEFAULT false,
+		recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+		last_used_step BIGINT NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		confirmed_at TIMESTAMPTZ
+	);
+
+	CREATE TABLE IF NOT EXISTS passkeys (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		credential_id BYTEA NOT NULL UNIQUE,
+		public_key BYTEA NOT NULL,
+		attestation_type VARCHAR(32) NOT NULL,
+		transports TEXT[] NOT NULL DEFAULT '{}',
+		sign_count BIGINT NOT NULL DEFAULT 0,
+		aaguid BYTEA,
+		backup_eligible BOOLEAN NOT NULL DEFAULT false,
+		backup_state BOOLEAN NOT NULL DEFAULT false,
+		name VARCHAR(100) NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		last_used_at TIMESTAMPTZ
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_passkeys_user_id ON passkeys(user_id);
+
+	CREATE TABLE IF NOT EXISTS webauthn_sessions (
+		id UUID PRIMARY KEY,
+		ceremony VARCHAR(20) NOT NULL,
+		user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+		data JSONB NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webauthn_sessions_expires_at ON webauthn_sessions(expires_at);
+
+	CREATE TABLE IF NOT EXISTS phone_otps (
+		user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		phone_number VARCHAR(16) NOT NULL,
+		code_hash VARCHAR(64) NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		expires_at TIMESTAMPTZ NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_phone_otps_expires_at ON phone_otps(expires_at);
+
+	CREATE TABLE IF NOT EXISTS sessions (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		ip VARCHAR(45) NOT NULL DEFAULT '',
+		user_agent VARCHAR(512) NOT NULL DEFAULT '',
+		amr TEXT[] NOT NULL DEFAULT '{}',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		expires_at TIMESTAMPTZ NOT NULL,
+		revoked_at TIMESTAMPTZ
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+	CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+
+	CREATE TABLE IF NOT EXISTS auth_events (
+		id BIGSERIAL PRIMARY KEY,
+		type VARCHAR(50) NOT NULL,
+		user_id VARCHAR(36) NOT NULL DEFAULT '',
+		identifier TEXT NOT NULL DEFAULT '',
+		ip VARCHAR(45) NOT NULL DEFAULT '',
+		user_agent VARCHAR(512) NOT NULL DEFAULT '',
+		outcome VARCHAR(20) NOT NULL DEFAULT '',
+		reason TEXT NOT NULL DEFAULT '',
+		correlation_id VARCHAR(128) NOT NULL DEFAULT '',
+		metadata JSONB,
+		occurred_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_auth_events_user_id ON auth_events(user_id, id);
+	CREATE INDEX IF NOT EXISTS idx_auth_events_identifier ON auth_events(identifier, id);
+	CREATE INDEX IF NOT EXISTS idx_auth_events_ip ON auth_events(ip, id);
+	CREATE INDEX IF NOT EXISTS idx_auth_events_correlation_id ON auth_events(correlation_id);
+	CREATE INDEX IF NOT EXISTS idx_auth_events_occurred_at ON auth_events(occurred_at);
+
+	CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id UUID PRIMARY KEY,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		event_types TEXT[] NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id UUID PRIMARY KEY,
+		subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event_id UUID NOT NULL,
+		event_type VARCHAR(50) NOT NULL,
+		payload TEXT NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		delivered_at TIMESTAMPTZ
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries(status, created_at);
+
+	CREATE TABLE IF NOT EXISTS signing_keys (
+		kid VARCHAR(128) PRIMARY KEY,
+		algorithm VARCHAR(10) NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		encrypted_key TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		retired_at TIMESTAMPTZ,
+		verify_until TIMESTAMPTZ
+	);
+
+	CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+		key VARCHAR(255) PRIMARY KEY,
+		tokens DOUBLE PRECISION NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_expires_at ON rate_limit_buckets(expires_at);
 	`
 
 	_, err := pool.Exec(context.Background(), query)
@@ -39,23 +228,186 @@ func RunMigrations(pool *pgxpool.Pool) error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	for _, index := range uniqueUserIndexes {
+		if err := createUniqueIndex(context.Background(), pool, index); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
+
+	if err := migratePhoneNumbers(context.Background(), pool); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
 	log.Println("PostgreSQL migrations completed successfully")
 	return nil
 }
 
+// uniqueIndex describes a unique index that replaces a plain one on a column that
+// was not unique until now
+type uniqueIndex struct {
+	name string
+	// expression is the indexed value, e.g. a column or lower(column)
+	expression string
+	replaces   string
+	// fix tells the operator what to do with the duplicates
+	fix string
+}
+
+// uniqueUserIndexes keep two concurrent registrations from taking the same nick
+// name, or the same email written with a different case
+var uniqueUserIndexes = []uniqueIndex{
+	{name: "idx_users_nick_name_unique", expression: "nick_name", replaces: "idx_users_nick_name", fix: "rename them"},
+	{name: "idx_users_email_lower", expression: "lower(email)", replaces: "idx_users_email", fix: "merge or change them"},
+}
+
+// createUniqueIndex replaces the plain index with the unique one. If the table
+// already holds duplicates the migration fails listing them, since deciding
+// which account keeps the value is a decision for an operator.
+func createUniqueIndex(ctx context.Context, pool *pgxpool.Pool, index uniqueIndex) error {
+	var indexExists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, index.name).Scan(&indexExists); err != nil {
+		return fmt.Errorf("failed to check index %s: %w", index.name, err)
+	}
+	if indexExists {
+		return nil
+	}
+
+	rows, err := pool.Query(ctx, fmt.Sprintf(`
+		SELECT %[1]s, string_agg(id::text, ', ' ORDER BY date_created, id)
+		FROM users
+		GROUP BY %[1]s
+		HAVING COUNT(*) > 1
+		ORDER BY %[1]s
+	`, index.expression))
+	if err != nil {
+		return fmt.Errorf("failed to look for duplicates of %s: %w", index.expression, err)
+	}
+	var conflicts []string
+	for rows.Next() {
+		var value, ids string
+		if err := rows.Scan(&value, &ids); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan duplicate of %s: %w", index.expression, err)
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s (users %s)", value, ids))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to look for duplicates of %s: %w", index.expression, err)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("cannot create unique index on users %s, these values belong to more than one user: %s; %s and restart", index.expression, strings.Join(conflicts, "; "), index.fix)
+	}
+
+	query := fmt.Sprintf(`
+	CREATE UNIQUE INDEX IF NOT EXISTS %s ON users(%s);
+	DROP INDEX IF EXISTS %s;
+	`, index.name, index.expression, index.replaces)
+	if _, err := pool.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", index.name, err)
+	}
+	return nil
+}
+
+// migratePhoneNumbers rewrites the stored phone numbers to E.164 before creating
+// the unique index on users.phone_number, so that the same number written in two
+// formats is detected as a duplicate instead of slipping past the index. Numbers
+// that cannot be normalized (no country code) are kept as they are. If two users
+// end up with the same number the migration fails listing them, since picking
+// the owner of the number is a decision for an operator.
+func migratePhoneNumbers(ctx context.Context, pool *pgxpool.Pool) error {
+	var indexExists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('idx_users_phone_number') IS NOT NULL`).Scan(&indexExists); err != nil {
+		return fmt.Errorf("failed to check phone number index: %w", err)
+	}
+	if indexExists {
+		// Every number written since the index exists has gone through the service normalization
+		return nil
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin phone number migration: %w", err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	// Empty phone numbers become NULL so the unique index only covers real numbers
+	if _, err := tx.Exec(ctx, `UPDATE users SET phone_number = NULL WHERE phone_number = ''`); err != nil {
+		return fmt.Errorf("failed to clear empty phone numbers: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id, phone_number FROM users WHERE phone_number IS NOT NULL ORDER BY date_created, id`)
+	if err != nil {
+		return fmt.Errorf("failed to list phone numbers: %w", err)
+	}
+	owners := make(map[string][]string)
+	updates := make(map[string]string)
+	var order []string
+	for rows.Next() {
+		var id, phoneNumber string
+		if err := rows.Scan(&id, &phoneNumber); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan phone number: %w", err)
+		}
+		normalized, err := domain.NormalizePhoneNumber(phoneNumber)
+		if err != nil {
+			log.Printf("Phone number of user %s is not in international format and is kept as is", id)
+			normalized = phoneNumber
+		} else if normalized != phoneNumber {
+			updates[id] = normalized
+		}
+		if len(owners[normalized]) == 0 {
+			order = append(order, normalized)
+		}
+		owners[normalized] = append(owners[normalized], id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list phone numbers: %w", err)
+	}
+
+	var conflicts []string
+	for _, phoneNumber := range order {
+		if ids := owners[phoneNumber]; len(ids) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s (users %s)", phoneNumber, strings.Join(ids, ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("cannot create unique index on users.phone_number, these numbers belong to more than one user once normalized to E.164: %s; clear or fix them and restart", strings.Join(conflicts, "; "))
+	}
+
+	for id, phoneNumber := range updates {
+		if _, err := tx.Exec(ctx, `UPDATE users SET phone_number = $1, date_updated = NOW() WHERE id = $2`, phoneNumber, id); err != nil {
+			return fmt.Errorf("failed to normalize phone number of user %s: %w", id, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number ON users(phone_number) WHERE phone_number IS NOT NULL`); err != nil {
+		return fmt.Errorf("failed to create phone number index: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit phone number migration: %w", err)
+	}
+
+	if len(updates) > 0 {
+		log.Printf("Normalized %d phone numbers to E.164", len(updates))
+	}
+	return nil
+}
+
 type User struct {
-	ID          string    `json:"id"`
-	NickName    string    `json:"nick_name"`
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
-	Email       string    `json:"email"`
-	Password    string    `json:"password"`
-	IsActive    bool      `json:"is_active"`
-	Role        string    `json:"role"`
-	PhoneNumber *string   `json:"phone_number"`
-	HasPassword bool      `json:"has_password"`
-	DateCreated time.Time `json:"date_created"`
-	DateUpdated time.Time `json:"date_updated"`
+	ID            string    `json:"id"`
+	NickName      string    `json:"nick_name"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Email         string    `json:"email"`
+	Password      string    `json:"password"`
+	IsActive      bool      `json:"is_active"`
+	Role          string    `json:"role"`
+	PhoneNumber   *string   `json:"phone_number"`
+	HasPassword   bool      `json:"has_password"`
+	EmailVerified bool      `json:"email_verified"`
+	DateCreated   time.Time `json:"date_created"`
+	DateUpdated   time.Time `json:"date_updated"`
 }
 
 func toDomainUser(user *User) *domain.User {
@@ -65,17 +417,18 @@ func toDomainUser(user *User) *domain.User {
 	}
 
 	return &domain.User{
-		ID:          user.ID,
-		NickName:    user.NickName,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		Password:    user.Password,
-		IsActive:    user.IsActive,
-		Role:        user.Role,
-		PhoneNumber: phoneNumber,
-		HasPassword: user.HasPassword,
-		DateCreated: user.DateCreated,
-		DateUpdated: user.DateUpdated,
+		ID:            user.ID,
+		NickName:      user.NickName,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		Password:      user.Password,
+		IsActive:      user.IsActive,
+		Role:          user.Role,
+		PhoneNumber:   phoneNumber,
+		HasPassword:   user.HasPassword,
+		EmailVerified: user.EmailVerified,
+		DateCreated:   user.DateCreated,
+		DateUpdated:   user.DateUpdated,
 	}
 }
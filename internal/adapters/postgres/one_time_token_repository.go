@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type oneTimeTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOneTimeTokenRepository(pool *pgxpool.Pool) ports.OneTimeTokenRepository {
+	return &oneTimeTokenRepository{pool: pool}
+}
+
+func (r *oneTimeTokenRepository) Create(ctx context.Context, token *domain.OneTimeToken) error {
+	query := `
+		INSERT INTO one_time_tokens (id, purpose, token_hash, user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		token.ID, token.Purpose, token.TokenHash, token.UserID, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create one-time token: %w", err)
+	}
+	return nil
+}
+
+func (r *oneTimeTokenRepository) Consume(ctx context.Context, purpose domain.OneTimeTokenPurpose, tokenHash string) (*domain.OneTimeToken, error) {
+	// The conditional UPDATE guarantees a token can only be consumed once
+	query := `
+		UPDATE one_time_tokens SET consumed_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND consumed_at IS NULL AND expires_at > NOW()
+		RETURNING id, purpose, token_hash, user_id, expires_at, created_at, consumed_at
+	`
+	token := &domain.OneTimeToken{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, tokenHash, purpose).Scan(
+		&token.ID, &token.Purpose, &token.TokenHash, &token.UserID,
+		&token.ExpiresAt, &token.CreatedAt, &token.ConsumedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOneTimeTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to consume one-time token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *oneTimeTokenRepository) InvalidateForUser(ctx context.Context, userID string, purpose domain.OneTimeTokenPurpose) error {
+	query := `UPDATE one_time_tokens SET consumed_at = NOW() WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, userID, purpose); err != nil {
+		return fmt.Errorf("failed to invalidate one-time tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *oneTimeTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM one_time_tokens WHERE expires_at <= NOW()`
+	result, err := conn(ctx, r.pool).Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired one-time tokens: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type passkeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasskeyRepository(pool *pgxpool.Pool) ports.PasskeyRepository {
+	return &passkeyRepository{pool: pool}
+}
+
+func (r *passkeyRepository) Create(ctx context.Context, passkey *domain.Passkey) error {
+	query := `
+		INSERT INTO passkeys (
+			id, user_id, credential_id, public_key, attestation_type, transports, sign_count,
+			aaguid, backup_eligible, backup_state, name, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		passkey.ID, passkey.UserID, passkey.CredentialID, passkey.PublicKey, passkey.AttestationType,
+		passkey.Transports, int64(passkey.SignCount), passkey.AAGUID, passkey.BackupEligible,
+		passkey.BackupState, passkey.Name, passkey.CreatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.ErrPasskeyAlreadyRegistered
+		}
+		return fmt.Errorf("failed to create passkey: %w", err)
+	}
+	return nil
+}
+
+func (r *passkeyRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Passkey, error) {
+	query := `
+		SELECT id, user_id, credential_id, public_key, attestation_type, transports, sign_count,
+			aaguid, backup_eligible, backup_state, name, created_at, last_used_at
+		FROM passkeys WHERE user_id = $1 ORDER BY created_at
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	defer rows.Close()
+
+	passkeys := make([]*domain.Passkey, 0)
+	for rows.Next() {
+		passkey := &domain.Passkey{}
+		var signCount int64
+		if err := rows.Scan(
+			&passkey.ID, &passkey.UserID, &passkey.CredentialID, &passkey.PublicKey, &passkey.AttestationType,
+			&passkey.Transports, &signCount, &passkey.AAGUID, &passkey.BackupEligible, &passkey.BackupState,
+			&passkey.Name, &passkey.CreatedAt, &passkey.LastUsedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan passkey: %w", err)
+		}
+		passkey.SignCount = uint32(signCount)
+		passkeys = append(passkeys, passkey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	return passkeys, nil
+}
+
+func (r *passkeyRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM passkeys WHERE user_id = $1`
+	var count int
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count passkeys: %w", err)
+	}
+	return count, nil
+}
+
+func (r *passkeyRepository) RecordUse(ctx context.Context, id string, signCount uint32, backupState bool, usedAt time.Time) error {
+	query := `UPDATE passkeys SET sign_count = $2, backup_state = $3, last_used_at = $4 WHERE id = $1`
+	if _, err := r.pool.Exec(ctx, query, id, int64(signCount), backupState, usedAt); err != nil {
+		return fmt.Errorf("failed to record passkey use: %w", err)
+	}
+	return nil
+}
+
+func (r *passkeyRepository) Delete(ctx context.Context, userID, id string) error {
+	query := `DELETE FROM passkeys WHERE user_id = $1 AND id = $2`
+	result, err := r.pool.Exec(ctx, query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrPasskeyNotFound
+	}
+	return nil
+}
+
+type webAuthnSessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebAuthnSessionRepository(pool *pgxpool.Pool) ports.WebAuthnSessionRepository {
+	return &webAuthnSessionRepository{pool: pool}
+}
+
+func (r *webAuthnSessionRepository) Create(ctx context.Context, session *domain.WebAuthnSession) error {
+	query := `
+		INSERT INTO webauthn_sessions (id, ceremony, user_id, data, expires_at)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query, session.ID, session.Ceremony, session.UserID, session.Data, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webauthn session: %w", err)
+	}
+	return nil
+}
+
+func (r *webAuthnSessionRepository) Consume(ctx context.Context, id string, ceremony domain.WebAuthnCeremony) (*domain.WebAuthnSession, error) {
+	query := `
+		DELETE FROM webauthn_sessions
+		WHERE id = $1 AND ceremony = $2 AND expires_at > NOW()
+		RETURNING id, ceremony, COALESCE(user_id::text, ''), data, expires_at
+	`
+	session := &domain.WebAuthnSession{}
+	err := r.pool.QueryRow(ctx, query, id, ceremony).Scan(
+		&session.ID, &session.Ceremony, &session.UserID, &session.Data, &session.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWebAuthnSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to consume webauthn session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *webAuthnSessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM webauthn_sessions WHERE expires_at <= NOW()`
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired webauthn sessions: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type phoneOTPRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPhoneOTPRepository(pool *pgxpool.Pool) ports.PhoneOTPRepository {
+	return &phoneOTPRepository{pool: pool}
+}
+
+func (r *phoneOTPRepository) Save(ctx context.Context, otp *domain.PhoneOTP) error {
+	query := `
+		INSERT INTO phone_otps (user_id, phone_number, code_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, 0, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			phone_number = EXCLUDED.phone_number,
+			code_hash = EXCLUDED.code_hash,
+			attempts = 0,
+			expires_at = EXCLUDED.expires_at,
+			created_at = EXCLUDED.created_at
+	`
+	_, err := r.pool.Exec(ctx, query, otp.UserID, otp.PhoneNumber, otp.CodeHash, otp.ExpiresAt, otp.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save phone otp: %w", err)
+	}
+	return nil
+}
+
+func (r *phoneOTPRepository) ReserveAttempt(ctx context.Context, userID string, maxAttempts int) (*domain.PhoneOTP, error) {
+	// Counting the attempt before comparing the code keeps concurrent guesses within the limit
+	query := `
+		UPDATE phone_otps SET attempts = attempts + 1
+		WHERE user_id = $1 AND attempts < $2 AND expires_at > NOW()
+		RETURNING user_id, phone_number, code_hash, attempts, expires_at, created_at
+	`
+	otp := &domain.PhoneOTP{}
+	err := r.pool.QueryRow(ctx, query, userID, maxAttempts).Scan(
+		&otp.UserID, &otp.PhoneNumber, &otp.CodeHash, &otp.Attempts, &otp.ExpiresAt, &otp.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidSMSCode
+		}
+		return nil, fmt.Errorf("failed to reserve phone otp attempt: %w", err)
+	}
+	return otp, nil
+}
+
+func (r *phoneOTPRepository) Consume(ctx context.Context, userID, codeHash string) error {
+	query := `DELETE FROM phone_otps WHERE user_id = $1 AND code_hash = $2`
+	result, err := r.pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to consume phone otp: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrInvalidSMSCode
+	}
+	return nil
+}
+
+func (r *phoneOTPRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM phone_otps WHERE expires_at <= NOW()`
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired phone otps: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
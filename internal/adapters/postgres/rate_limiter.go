@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type rateLimiter struct {
+	pool *pgxpool.Pool
+}
+
+// NewRateLimiter creates a rate limiter whose buckets are shared by every instance through PostgreSQL
+func NewRateLimiter(pool *pgxpool.Pool) ports.RateLimiter {
+	return &rateLimiter{pool: pool}
+}
+
+func (r *rateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// New buckets start full; the row lock serializes concurrent requests for the same key
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (key) DO NOTHING
+	`, key, limit.Burst)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limit bucket: %w", err)
+	}
+
+	var tokens, elapsedSeconds float64
+	err = tx.QueryRow(ctx, `
+		SELECT tokens, EXTRACT(EPOCH FROM NOW() - updated_at)::DOUBLE PRECISION
+		FROM rate_limit_buckets WHERE key = $1 FOR UPDATE
+	`, key).Scan(&tokens, &elapsedSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit bucket: %w", err)
+	}
+
+	tokens, result := limit.Take(tokens, time.Duration(elapsedSeconds*float64(time.Second)))
+
+	_, err = tx.Exec(ctx, `
+		UPDATE rate_limit_buckets SET tokens = $2, updated_at = NOW(), expires_at = NOW() + $3 * INTERVAL '1 microsecond'
+		WHERE key = $1
+	`, key, tokens, result.ResetAfter.Microseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rate limit bucket: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit rate limit bucket: %w", err)
+	}
+	return &result, nil
+}
+
+func (r *rateLimiter) DeleteExpired(ctx context.Context) (int64, error) {
+	// A bucket that has fully refilled behaves exactly like a missing one
+	query := `DELETE FROM rate_limit_buckets WHERE expires_at <= NOW()`
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limit buckets: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
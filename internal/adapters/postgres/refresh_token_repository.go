@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type refreshTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(pool *pgxpool.Pool) ports.RefreshTokenRepository {
+	return &refreshTokenRepository{pool: pool}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *domain.RefreshTokenRecord) error {
+	query := `
+		INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		token.ID, token.FamilyID, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshTokenRecord, error) {
+	query := `SELECT id, family_id, user_id, token_hash, expires_at, created_at, rotated_at, revoked_at FROM refresh_tokens WHERE token_hash = $1 LIMIT 1`
+	token := &domain.RefreshTokenRecord{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID, &token.FamilyID, &token.UserID, &token.TokenHash,
+		&token.ExpiresAt, &token.CreatedAt, &token.RotatedAt, &token.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *refreshTokenRepository) Rotate(ctx context.Context, currentID string, next *domain.RefreshTokenRecord) error {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Solo una rotación puede ganar: el UPDATE condicional bloquea la fila y falla si ya fue usada
+	result, err := tx.Exec(ctx,
+		`UPDATE refresh_tokens SET rotated_at = NOW() WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`,
+		currentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, next.ID, next.FamilyID, next.UserID, next.TokenHash, next.ExpiresAt, next.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit refresh token rotation: %w", err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
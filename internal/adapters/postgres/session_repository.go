@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type sessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSessionRepository(pool *pgxpool.Pool) ports.SessionRepository {
+	return &sessionRepository{pool: pool}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, ip, user_agent, amr, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	methods := session.Methods
+	if methods == nil {
+		methods = []string{}
+	}
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		session.ID, session.UserID, session.IP, session.UserAgent, methods,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Get(ctx context.Context, id string) (*domain.Session, error) {
+	query := `
+		SELECT id, user_id, ip, user_agent, amr, created_at, last_seen_at, expires_at, revoked_at
+		FROM sessions WHERE id = $1
+	`
+	session := &domain.Session{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
+		&session.ID, &session.UserID, &session.IP, &session.UserAgent, &session.Methods,
+		&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	query := `
+		SELECT id, user_id, ip, user_agent, amr, created_at, last_seen_at, expires_at, revoked_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]*domain.Session, 0)
+	for rows.Next() {
+		session := &domain.Session{}
+		if err := rows.Scan(
+			&session.ID, &session.UserID, &session.IP, &session.UserAgent, &session.Methods,
+			&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id string, seenAt, expiresAt time.Time) error {
+	query := `UPDATE sessions SET last_seen_at = $2, expires_at = $3 WHERE id = $1 AND revoked_at IS NULL`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, seenAt, expiresAt); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, userID, id string) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND id = $2 AND revoked_at IS NULL`
+	result, err := conn(ctx, r.pool).Exec(ctx, query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) IsKnownDevice(ctx context.Context, userID, userAgent string) (bool, error) {
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM sessions WHERE user_id = $1)
+			OR EXISTS (SELECT 1 FROM sessions WHERE user_id = $1 AND user_agent = $2)
+	`
+	var known bool
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, userID, userAgent).Scan(&known); err != nil {
+		return false, fmt.Errorf("failed to check known device: %w", err)
+	}
+	return known, nil
+}
+
+func (r *sessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM sessions WHERE expires_at <= NOW()`
+	result, err := conn(ctx, r.pool).Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
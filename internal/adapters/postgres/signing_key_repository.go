@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type signingKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSigningKeyRepository(pool *pgxpool.Pool) ports.SigningKeyRepository {
+	return &signingKeyRepository{pool: pool}
+}
+
+func (r *signingKeyRepository) Activate(ctx context.Context, key *domain.SigningKeyRecord, verifyUntil time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Concurrent rotations from several replicas must leave a single active key
+	if _, err := tx.Exec(ctx, `LOCK TABLE signing_keys IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock signing keys: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM signing_keys WHERE kid = $1)`, key.Kid).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check signing key: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	retire := `
+		UPDATE signing_keys SET status = $1, retired_at = $2, verify_until = $3
+		WHERE status = $4
+	`
+	if _, err := tx.Exec(ctx, retire,
+		string(domain.SigningKeyRetired), key.CreatedAt, verifyUntil, string(domain.SigningKeyActive),
+	); err != nil {
+		return fmt.Errorf("failed to retire signing keys: %w", err)
+	}
+
+	insert := `
+		INSERT INTO signing_keys (kid, algorithm, status, encrypted_key, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(ctx, insert,
+		key.Kid, key.Algorithm, string(domain.SigningKeyActive), key.EncryptedKey, key.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to store signing key: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit signing key: %w", err)
+	}
+	return nil
+}
+
+func (r *signingKeyRepository) Retire(ctx context.Context, key *domain.SigningKeyRecord, verifyUntil time.Time) error {
+	query := `
+		INSERT INTO signing_keys (kid, algorithm, status, encrypted_key, created_at, retired_at, verify_until)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
+		ON CONFLICT (kid) DO UPDATE SET
+			status = EXCLUDED.status,
+			retired_at = COALESCE(signing_keys.retired_at, EXCLUDED.retired_at),
+			verify_until = COALESCE(signing_keys.verify_until, EXCLUDED.verify_until)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		key.Kid, key.Algorithm, string(domain.SigningKeyRetired), key.EncryptedKey, key.CreatedAt, verifyUntil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to retire signing key: %w", err)
+	}
+	return nil
+}
+
+func (r *signingKeyRepository) List(ctx context.Context) ([]*domain.SigningKeyRecord, error) {
+	query := `
+		SELECT kid, algorithm, status, encrypted_key, created_at, retired_at, verify_until
+		FROM signing_keys
+		ORDER BY created_at DESC
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.SigningKeyRecord, 0)
+	for rows.Next() {
+		key := &domain.SigningKeyRecord{}
+		var status string
+		if err := rows.Scan(
+			&key.Kid, &key.Algorithm, &status, &key.EncryptedKey, &key.CreatedAt, &key.RetiredAt, &key.VerifyUntil,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		key.Status = domain.SigningKeyStatus(status)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *signingKeyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM signing_keys WHERE verify_until <= NOW() AND encrypted_key <> ''`
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired signing keys: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type tokenRevocationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTokenRevocationRepository(pool *pgxpool.Pool) ports.TokenRevocationRepository {
+	return &tokenRevocationRepository{pool: pool}
+}
+
+func (r *tokenRevocationRepository) Revoke(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, jti, userID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRevocationRepository) Consume(ctx context.Context, jti, userID string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (jti) DO NOTHING
+	`
+	result, err := conn(ctx, r.pool).Exec(ctx, query, jti, userID, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume token: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *tokenRevocationRepository) RevokeAllForUser(ctx context.Context, userID string, issuedBefore time.Time) error {
+	query := `
+		INSERT INTO user_token_revocations (user_id, revoked_before)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query, userID, issuedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRevocationRepository) IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1 AND expires_at > NOW())
+			OR EXISTS(SELECT 1 FROM user_token_revocations WHERE user_id = $2 AND revoked_before >= $3)
+	`
+	var revoked bool
+	err := conn(ctx, r.pool).QueryRow(ctx, query, jti, userID, issuedAt).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return revoked, nil
+}
+
+func (r *tokenRevocationRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM revoked_tokens WHERE expires_at <= NOW()`
+	result, err := conn(ctx, r.pool).Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired revocations: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
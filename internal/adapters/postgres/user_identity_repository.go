@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+type userIdentityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(pool *pgxpool.Pool) ports.UserIdentityRepository {
+	return &userIdentityRepository{pool: pool}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (provider, provider_subject, user_id, email_at_link, linked_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		identity.Provider, identity.ProviderSubject, identity.UserID, identity.EmailAtLink, identity.LinkedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.ErrIdentityAlreadyLinked
+		}
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	query := `SELECT provider, provider_subject, user_id, email_at_link, linked_at FROM user_identities WHERE provider = $1 AND provider_subject = $2`
+	identity := &domain.UserIdentity{}
+	err := r.pool.QueryRow(ctx, query, provider, subject).Scan(
+		&identity.Provider, &identity.ProviderSubject, &identity.UserID, &identity.EmailAtLink, &identity.LinkedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrIdentityNotFound
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	return identity, nil
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID string) ([]*domain.UserIdentity, error) {
+	query := `SELECT provider, provider_subject, user_id, email_at_link, linked_at FROM user_identities WHERE user_id = $1 ORDER BY linked_at`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := make([]*domain.UserIdentity, 0)
+	for rows.Next() {
+		identity := &domain.UserIdentity{}
+		if err := rows.Scan(
+			&identity.Provider, &identity.ProviderSubject, &identity.UserID, &identity.EmailAtLink, &identity.LinkedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan user identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+	return identities, nil
+}
+
+func (r *userIdentityRepository) Delete(ctx context.Context, userID, provider string) error {
+	query := `DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`
+	result, err := r.pool.Exec(ctx, query, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to delete user identity: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrIdentityNotFound
+	}
+	return nil
+}
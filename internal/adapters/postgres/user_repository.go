@@ -10,9 +10,17 @@ import (
 	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	// phoneNumberIndex is the unique index that keeps phone numbers from being shared between accounts
+	phoneNumberIndex = "idx_users_phone_number"
+	// nickNameIndex is the unique index that keeps nick names from being shared between accounts
+	nickNameIndex = "idx_users_nick_name_unique"
+)
+
 type userRepository struct {
 	pool *pgxpool.Pool
 }
@@ -23,27 +31,37 @@ func NewUserRepository(pool *pgxpool.Pool) ports.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, date_created, date_updated)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO users (id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13)
 	`
-	_, err := r.pool.Exec(ctx, query,
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
 		user.ID, user.NickName, user.FirstName, user.LastName,
 		user.Email, user.Password, user.IsActive, user.Role,
-		user.PhoneNumber, user.HasPassword, time.Now(), time.Now(),
+		user.PhoneNumber, user.HasPassword, user.EmailVerified, time.Now(), time.Now(),
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			switch pgErr.ConstraintName {
+			case phoneNumberIndex:
+				return domain.ErrPhoneNumberAlreadyRegistered
+			case nickNameIndex:
+				return domain.ErrNickNameTaken
+			}
+			return domain.ErrUserAlreadyExists
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 	return nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, date_created, date_updated FROM users WHERE id = $1 LIMIT 1`
+	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated FROM users WHERE id = $1 LIMIT 1`
 	user := &User{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.NickName, &user.FirstName, &user.LastName,
 		&user.Email, &user.Password, &user.IsActive, &user.Role,
-		&user.PhoneNumber, &user.HasPassword, &user.DateCreated, &user.DateUpdated,
+		&user.PhoneNumber, &user.HasPassword, &user.EmailVerified, &user.DateCreated, &user.DateUpdated,
 	)
 	if err != nil {
 		return nil, domain.ErrUserNotFound
@@ -52,12 +70,12 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User,
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, date_created, date_updated FROM users WHERE email = $1 LIMIT 1`
+	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated FROM users WHERE lower(email) = lower($1) LIMIT 1`
 	user := &User{}
-	err := r.pool.QueryRow(ctx, query, email).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.NickName, &user.FirstName, &user.LastName,
 		&user.Email, &user.Password, &user.IsActive, &user.Role,
-		&user.PhoneNumber, &user.HasPassword, &user.DateCreated, &user.DateUpdated,
+		&user.PhoneNumber, &user.HasPassword, &user.EmailVerified, &user.DateCreated, &user.DateUpdated,
 	)
 	if err != nil {
 		return nil, domain.ErrUserNotFound
@@ -65,13 +83,30 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return toDomainUser(user), nil
 }
 
+func (r *userRepository) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*domain.User, error) {
+	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated FROM users WHERE phone_number = $1 LIMIT 1`
+	user := &User{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, phoneNumber).Scan(
+		&user.ID, &user.NickName, &user.FirstName, &user.LastName,
+		&user.Email, &user.Password, &user.IsActive, &user.Role,
+		&user.PhoneNumber, &user.HasPassword, &user.EmailVerified, &user.DateCreated, &user.DateUpdated,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by phone number: %w", err)
+	}
+	return toDomainUser(user), nil
+}
+
 func (r *userRepository) GetByNickName(ctx context.Context, nickName string) (*domain.User, error) {
-	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, date_created, date_updated FROM users WHERE nick_name = $1 LIMIT 1`
+	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated FROM users WHERE nick_name = $1 LIMIT 1`
 	user := &User{}
-	err := r.pool.QueryRow(ctx, query, nickName).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, nickName).Scan(
 		&user.ID, &user.NickName, &user.FirstName, &user.LastName,
 		&user.Email, &user.Password, &user.IsActive, &user.Role,
-		&user.PhoneNumber, &user.HasPassword, &user.DateCreated, &user.DateUpdated,
+		&user.PhoneNumber, &user.HasPassword, &user.EmailVerified, &user.DateCreated, &user.DateUpdated,
 	)
 	if err != nil {
 		return nil, domain.ErrUserNotFound
@@ -80,12 +115,12 @@ func (r *userRepository) GetByNickName(ctx context.Context, nickName string) (*d
 }
 
 func (r *userRepository) GetByEmailOrNickName(ctx context.Context, emailOrNickName string) (*domain.User, error) {
-	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, date_created, date_updated FROM users WHERE nick_name = $1 OR email = $2 LIMIT 1`
+	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated FROM users WHERE nick_name = $1 OR lower(email) = lower($2) LIMIT 1`
 	user := &User{}
-	err := r.pool.QueryRow(ctx, query, emailOrNickName, emailOrNickName).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, emailOrNickName, emailOrNickName).Scan(
 		&user.ID, &user.NickName, &user.FirstName, &user.LastName,
 		&user.Email, &user.Password, &user.IsActive, &user.Role,
-		&user.PhoneNumber, &user.HasPassword, &user.DateCreated, &user.DateUpdated,
+		&user.PhoneNumber, &user.HasPassword, &user.EmailVerified, &user.DateCreated, &user.DateUpdated,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -99,8 +134,8 @@ func (r *userRepository) GetByEmailOrNickName(ctx context.Context, emailOrNickNa
 }
 
 func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.User, error) {
-	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, date_created, date_updated FROM users ORDER BY date_created DESC LIMIT $1 OFFSET $2`
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	query := `SELECT id, nick_name, first_name, last_name, email, password, is_active, role, phone_number, has_password, email_verified, date_created, date_updated FROM users ORDER BY date_created DESC LIMIT $1 OFFSET $2`
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
@@ -112,7 +147,7 @@ func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]*doma
 		err := rows.Scan(
 			&user.ID, &user.NickName, &user.FirstName, &user.LastName,
 			&user.Email, &user.Password, &user.IsActive, &user.Role,
-			&user.PhoneNumber, &user.HasPassword, &user.DateCreated, &user.DateUpdated,
+			&user.PhoneNumber, &user.HasPassword, &user.EmailVerified, &user.DateCreated, &user.DateUpdated,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -123,13 +158,22 @@ func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]*doma
 }
 
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
-	query := `UPDATE users SET nick_name = $1, first_name = $2, last_name = $3, email = $4, password = $5, is_active = $6, role = $7, phone_number = $8, has_password = $9, date_updated = $10 WHERE id = $11`
-	result, err := r.pool.Exec(ctx, query,
+	query := `UPDATE users SET nick_name = $1, first_name = $2, last_name = $3, email = $4, password = $5, is_active = $6, role = $7, phone_number = NULLIF($8, ''), has_password = $9, email_verified = $10, date_updated = $11 WHERE id = $12`
+	result, err := conn(ctx, r.pool).Exec(ctx, query,
 		user.NickName, user.FirstName, user.LastName, user.Email,
 		user.Password, user.IsActive, user.Role, user.PhoneNumber,
-		user.HasPassword, time.Now(), user.ID,
+		user.HasPassword, user.EmailVerified, time.Now(), user.ID,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			switch pgErr.ConstraintName {
+			case phoneNumberIndex:
+				return domain.ErrPhoneNumberAlreadyRegistered
+			case nickNameIndex:
+				return domain.ErrNickNameTaken
+			}
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	if result.RowsAffected() == 0 {
@@ -140,7 +184,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 
 func (r *userRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
-	result, err := r.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, r.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -151,11 +195,31 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE lower(email) = lower($1))`
 	var exists bool
-	err := r.pool.QueryRow(ctx, query, email).Scan(&exists)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
 	return exists, nil
 }
+
+func (r *userRepository) ExistsByNickName(ctx context.Context, nickName string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE nick_name = $1)`
+	var exists bool
+	err := conn(ctx, r.pool).QueryRow(ctx, query, nickName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check nick name existence: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *userRepository) ExistsByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE phone_number = $1)`
+	var exists bool
+	err := conn(ctx, r.pool).QueryRow(ctx, query, phoneNumber).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check phone number existence: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const webhookDeliveryColumns = `
+	id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_error, last_status_code, created_at, delivered_at
+`
+
+type webhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) ports.WebhookRepository {
+	return &webhookRepository{pool: pool}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		subscription.ID, subscription.URL, subscription.Secret, eventTypeStrings(subscription.EventTypes), subscription.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `SELECT id, url, secret, event_types, created_at FROM webhook_subscriptions ORDER BY created_at`
+	rows, err := conn(ctx, r.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*domain.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	query := `SELECT id, url, secret, event_types, created_at FROM webhook_subscriptions WHERE id = $1`
+	subscription, err := scanWebhookSubscription(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWebhookSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+	result, err := conn(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) Enqueue(ctx context.Context, event domain.SecurityEvent, payload []byte) (int, error) {
+	tx, err := conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT id FROM webhook_subscriptions WHERE $1 = ANY(event_types)`, string(event.Type))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+	subscriptionIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+	if len(subscriptionIDs) == 0 {
+		return 0, nil
+	}
+
+	// Either every delivery of the event is stored or none is
+	batch := &pgx.Batch{}
+	for _, subscriptionID := range subscriptionIDs {
+		batch.Queue(`
+			INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		`, uuid.NewString(), subscriptionID, event.ID, string(event.Type), string(payload),
+			string(domain.WebhookDeliveryPending), event.OccurredAt)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit webhook deliveries: %w", err)
+	}
+	return len(subscriptionIDs), nil
+}
+
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	// SKIP LOCKED spreads deliveries across replicas. Pushing next_attempt_at forward hides them while
+	// they are being sent and puts them back in the queue if the replica dies before recording the result
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit, lease.Milliseconds(), string(domain.WebhookDeliveryPending))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return collectWebhookDeliveries(rows)
+}
+
+func (r *webhookRepository) UpdateAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, last_status_code = $6, delivered_at = $7
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.pool).Exec(ctx, query,
+		delivery.ID, string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastError, delivery.LastStatusCode, delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, status domain.WebhookDeliveryStatus, subscriptionID string, limit int) ([]*domain.WebhookDelivery, error) {
+	var conditions []string
+	var args []any
+	if status != "" {
+		args = append(args, string(status))
+		conditions = append(conditions, "status = $"+strconv.Itoa(len(args)))
+	}
+	if subscriptionID != "" {
+		args = append(args, subscriptionID)
+		conditions = append(conditions, "subscription_id = $"+strconv.Itoa(len(args)))
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+	query += " ORDER BY created_at DESC LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return collectWebhookDeliveries(rows)
+}
+
+func (r *webhookRepository) Replay(ctx context.Context, id string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = 0, next_attempt_at = NOW(), last_error = '', last_status_code = 0, delivered_at = NULL
+		WHERE id = $1
+	`
+	result, err := conn(ctx, r.pool).Exec(ctx, query, id, string(domain.WebhookDeliveryPending))
+	if err != nil {
+		return fmt.Errorf("failed to replay webhook delivery: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+func scanWebhookSubscription(row pgx.Row) (*domain.WebhookSubscription, error) {
+	subscription := &domain.WebhookSubscription{}
+	var eventTypes []string
+	if err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, &eventTypes, &subscription.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+	}
+	for _, eventType := range eventTypes {
+		subscription.EventTypes = append(subscription.EventTypes, domain.SecurityEventType(eventType))
+	}
+	return subscription, nil
+}
+
+func collectWebhookDeliveries(rows pgx.Rows) ([]*domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]*domain.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery := &domain.WebhookDelivery{}
+		var eventType, payload, status string
+		if err := rows.Scan(
+			&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &eventType, &payload, &status,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError, &delivery.LastStatusCode,
+			&delivery.CreatedAt, &delivery.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		delivery.EventType = domain.SecurityEventType(eventType)
+		delivery.Payload = []byte(payload)
+		delivery.Status = domain.WebhookDeliveryStatus(status)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func eventTypeStrings(eventTypes []domain.SecurityEventType) []string {
+	values := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		values = append(values, string(eventType))
+	}
+	return values
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// fullAt es el momento en que el bucket vuelve a estar lleno y puede descartarse
+	fullAt time.Time
+}
+
+type memoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryRateLimiter crea un rate limiter en memoria.
+// Cada instancia lleva su propia cuenta, por lo que con varias réplicas los límites se multiplican.
+func NewMemoryRateLimiter() ports.RateLimiter {
+	return &memoryRateLimiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow consume un token del bucket de la clave
+func (l *memoryRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitResult, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	tokens, result := limit.Take(b.tokens, now.Sub(b.updatedAt))
+	b.tokens = tokens
+	b.updatedAt = now
+	b.fullAt = now.Add(result.ResetAfter)
+
+	return &result, nil
+}
+
+// DeleteExpired descarta los buckets que ya se han recargado por completo
+func (l *memoryRateLimiter) DeleteExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var deleted int64
+	for key, b := range l.buckets {
+		if !b.fullAt.After(now) {
+			delete(l.buckets, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,23 @@
+package sms
+
+import (
+	"context"
+	"log"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// logSender implementa SMSSender para desarrollo local: escribe los SMS en el log
+type logSender struct{}
+
+// NewLogSender crea un SMSSender que no envía nada y escribe cada SMS en el log
+func NewLogSender() ports.SMSSender {
+	return &logSender{}
+}
+
+// Send escribe el SMS en el log
+func (s *logSender) Send(ctx context.Context, message domain.SMSMessage) error {
+	log.Printf("sms to=%s\n%s", message.To, message.Body)
+	return nil
+}
@@ -0,0 +1,71 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// WebhookConfig contiene la configuración del webhook que entrega los SMS al proveedor
+type WebhookConfig struct {
+	URL string
+	// Token se envía como Authorization: Bearer; vacío no envía la cabecera
+	Token   string
+	Timeout time.Duration
+}
+
+// webhookPayload es el cuerpo JSON que recibe el webhook
+type webhookPayload struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+// webhookSender implementa SMSSender con un POST JSON a un webhook genérico (gateway propio, Twilio Functions...)
+type webhookSender struct {
+	config WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookSender crea un SMSSender que entrega cada SMS al webhook configurado
+func NewWebhookSender(config WebhookConfig) ports.SMSSender {
+	return &webhookSender{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Send envía el SMS al webhook; cualquier respuesta fuera de 2xx se considera un error
+func (s *webhookSender) Send(ctx context.Context, message domain.SMSMessage) error {
+	body, err := json.Marshal(webhookPayload{To: message.To, Body: message.Body})
+	if err != nil {
+		return fmt.Errorf("failed to encode sms payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sms webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call sms webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -42,6 +42,7 @@ CREATE TABLE IF NOT EXISTS users (
 
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_users_date_created ON users(date_created);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number ON users(phone_number) WHERE phone_number IS NOT NULL;
 		`)
 		return fmt.Errorf("users table not found, please create it in Supabase Dashboard: %w", err)
 	}
@@ -52,52 +53,66 @@ CREATE INDEX IF NOT EXISTS idx_users_date_created ON users(date_created);
 
 // User represents the database schema for Supabase
 type User struct {
-	ID          string    `json:"id"`
-	NickName    string    `json:"nick_name"`
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
-	Email       string    `json:"email"`
-	Password    string    `json:"password"`
-	IsActive    bool      `json:"is_active"`
-	Role        string    `json:"role"`
-	PhoneNumber string    `json:"phone_number"`
-	HasPassword bool      `json:"has_password"`
-	DateCreated time.Time `json:"date_created"`
-	DateUpdated time.Time `json:"date_updated"`
+	ID            string    `json:"id"`
+	NickName      string    `json:"nick_name"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Email         string    `json:"email"`
+	Password      string    `json:"password"`
+	IsActive      bool      `json:"is_active"`
+	Role          string    `json:"role"`
+	PhoneNumber   *string   `json:"phone_number"`
+	HasPassword   bool      `json:"has_password"`
+	EmailVerified bool      `json:"email_verified"`
+	DateCreated   time.Time `json:"date_created"`
+	DateUpdated   time.Time `json:"date_updated"`
 }
 
-// toSupabaseUser converts domain.User to Supabase User
+// toSupabaseUser converts domain.User to Supabase User.
+// An empty phone number is stored as NULL so it does not collide in the unique index.
 func toSupabaseUser(user *domain.User) *User {
+	var phoneNumber *string
+	if user.PhoneNumber != "" {
+		phoneNumber = &user.PhoneNumber
+	}
+
 	return &User{
-		ID:          user.ID,
-		NickName:    user.NickName,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		Password:    user.Password,
-		IsActive:    user.IsActive,
-		Role:        user.Role,
-		PhoneNumber: user.PhoneNumber,
-		HasPassword: user.HasPassword,
-		DateCreated: user.DateCreated,
-		DateUpdated: user.DateUpdated,
+		ID:            user.ID,
+		NickName:      user.NickName,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		Password:      user.Password,
+		IsActive:      user.IsActive,
+		Role:          user.Role,
+		PhoneNumber:   phoneNumber,
+		HasPassword:   user.HasPassword,
+		EmailVerified: user.EmailVerified,
+		DateCreated:   user.DateCreated,
+		DateUpdated:   user.DateUpdated,
 	}
 }
 
 // toDomainUser converts Supabase User to domain.User
 func toDomainUser(user *User) *domain.User {
+	var phoneNumber string
+	if user.PhoneNumber != nil {
+		phoneNumber = *user.PhoneNumber
+	}
+
 	return &domain.User{
-		ID:          user.ID,
-		NickName:    user.NickName,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		Password:    user.Password,
-		IsActive:    user.IsActive,
-		Role:        user.Role,
-		PhoneNumber: user.PhoneNumber,
-		HasPassword: user.HasPassword,
-		DateCreated: user.DateCreated,
-		DateUpdated: user.DateUpdated,
+		ID:            user.ID,
+		NickName:      user.NickName,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		Password:      user.Password,
+		IsActive:      user.IsActive,
+		Role:          user.Role,
+		PhoneNumber:   phoneNumber,
+		HasPassword:   user.HasPassword,
+		EmailVerified: user.EmailVerified,
+		DateCreated:   user.DateCreated,
+		DateUpdated:   user.DateUpdated,
 	}
 }
@@ -74,6 +74,25 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return toDomainUser(&users[0]), nil
 }
 
+// GetByPhoneNumber retrieves a user by their E.164 phone number
+func (r *userRepository) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*domain.User, error) {
+	var users []User
+	_, err := r.client.From("users").
+		Select("*", "", false).
+		Eq("phone_number", phoneNumber).
+		ExecuteTo(&users)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by phone number: %w", err)
+	}
+
+	if len(users) == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return toDomainUser(&users[0]), nil
+}
+
 // GetByNickName retrieves a user by their nick name
 func (r *userRepository) GetByNickName(ctx context.Context, nickName string) (*domain.User, error) {
 	var users []User
@@ -188,3 +207,33 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 
 	return len(users) > 0, nil
 }
+
+// ExistsByNickName checks if a user with the given nick name exists
+func (r *userRepository) ExistsByNickName(ctx context.Context, nickName string) (bool, error) {
+	var users []User
+	_, err := r.client.From("users").
+		Select("id", "", false).
+		Eq("nick_name", nickName).
+		ExecuteTo(&users)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check nick name existence: %w", err)
+	}
+
+	return len(users) > 0, nil
+}
+
+// ExistsByPhoneNumber checks if a user with the given phone number exists
+func (r *userRepository) ExistsByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error) {
+	var users []User
+	_, err := r.client.From("users").
+		Select("id", "", false).
+		Eq("phone_number", phoneNumber).
+		ExecuteTo(&users)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check phone number existence: %w", err)
+	}
+
+	return len(users) > 0, nil
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// Cabeceras de cada entrega; el receptor verifica la firma con domain.VerifyWebhookSignature
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const userAgent = "Bikes2Road-Webhooks/1.0"
+
+// httpSender implementa WebhookSender con un POST JSON firmado al endpoint de la suscripción
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender crea un WebhookSender con el timeout dado por entrega.
+// Las redirecciones no se siguen: una respuesta 3xx cuenta como entrega fallida.
+func NewHTTPSender(timeout time.Duration) ports.WebhookSender {
+	return &httpSender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send envía la entrega; cualquier respuesta fuera de 2xx se considera un error
+func (s *httpSender) Send(ctx context.Context, webhook domain.WebhookRequest) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(webhook.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderID, webhook.EventID)
+	req.Header.Set(HeaderEvent, string(webhook.EventType))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(webhook.Timestamp, 10))
+	req.Header.Set(HeaderSignature, webhook.Signature)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AuthEventType identifica el tipo de evento de auditoría
+type AuthEventType string
+
+// Eventos de las operaciones del servicio de autenticación; llevan siempre un resultado
+const (
+	AuthEventLogin             AuthEventType = "login"
+	AuthEventOAuthLogin        AuthEventType = "login_oauth"
+	AuthEventMFALogin          AuthEventType = "login_mfa"
+	AuthEventMFAPasskeyBegin   AuthEventType = "login_mfa_passkey_begin"
+	AuthEventPasskeyLoginBegin AuthEventType = "login_passkey_begin"
+	AuthEventPasskeyLogin      AuthEventType = "login_passkey"
+	AuthEventMagicLinkRequest  AuthEventType = "login_magic_link_request"
+	AuthEventMagicLinkLogin    AuthEventType = "login_magic_link"
+	AuthEventSMSCodeRequest    AuthEventType = "login_sms_request"
+	AuthEventSMSLogin          AuthEventType = "login_sms"
+	AuthEventRegister          AuthEventType = "register"
+	AuthEventTokenValidated    AuthEventType = "token_validated"
+	AuthEventTokenRefreshed    AuthEventType = "token_refreshed"
+	// AuthEventTokenRejected se registra cuando un endpoint protegido rechaza el access token
+	AuthEventTokenRejected     AuthEventType = "token_rejected"
+	AuthEventTokenIntrospected AuthEventType = "token_introspected"
+	AuthEventLogout            AuthEventType = "logout"
+	AuthEventLogoutAll         AuthEventType = "logout_all"
+	AuthEventUserInfo          AuthEventType = "userinfo"
+)
+
+const (
+	// AuthEventRefreshTokenReuse se registra cuando se presenta un refresh token ya rotado
+	AuthEventRefreshTokenReuse AuthEventType = "refresh_token_reuse"
+	// AuthEventPasswordReset se registra cuando un usuario restablece su contraseña con un enlace de recuperación
+	AuthEventPasswordReset AuthEventType = "password_reset"
+	// AuthEventPasswordChanged se registra en cada intento de un usuario de cambiar su contraseña
+	AuthEventPasswordChanged AuthEventType = "password_changed"
+	// AuthEventPasswordSet se registra en cada intento de un usuario sin contraseña de establecer la primera
+	AuthEventPasswordSet AuthEventType = "password_set"
+	// AuthEventAccountLocked se registra cuando una cuenta se bloquea por intentos de login fallidos
+	AuthEventAccountLocked AuthEventType = "account_locked"
+	// AuthEventAccountUnlocked se registra cuando un administrador desbloquea una cuenta
+	AuthEventAccountUnlocked AuthEventType = "account_unlocked"
+	// AuthEventAccountDeactivated se registra cuando un administrador desactiva una cuenta
+	AuthEventAccountDeactivated AuthEventType = "account_deactivated"
+	// AuthEventMFAEnabled se registra cuando un usuario confirma su segundo factor TOTP
+	AuthEventMFAEnabled AuthEventType = "mfa_enabled"
+	// AuthEventMFADisabled se registra cuando un usuario desactiva su segundo factor
+	AuthEventMFADisabled AuthEventType = "mfa_disabled"
+	// AuthEventRecoveryCodesRegenerated se registra cuando un usuario genera un nuevo lote de códigos de recuperación
+	AuthEventRecoveryCodesRegenerated AuthEventType = "mfa_recovery_codes_regenerated"
+	// AuthEventRecoveryCodeUsed se registra cuando se consume un código de recuperación
+	AuthEventRecoveryCodeUsed AuthEventType = "mfa_recovery_code_used"
+	// AuthEventSessionRevoked se registra cuando un usuario cierra una de sus sesiones desde la lista de dispositivos
+	AuthEventSessionRevoked AuthEventType = "session_revoked"
+	// AuthEventPasskeyRegistered se registra cuando un usuario añade una passkey
+	AuthEventPasskeyRegistered AuthEventType = "passkey_registered"
+	// AuthEventPasskeyRemoved se registra cuando un usuario elimina una passkey
+	AuthEventPasskeyRemoved AuthEventType = "passkey_removed"
+	// AuthEventPasskeyCloneDetected se registra cuando el contador de firmas de una passkey no avanza
+	AuthEventPasskeyCloneDetected AuthEventType = "passkey_clone_detected"
+	// AuthEventIdentityLinked se registra cuando se vincula una identidad externa a una cuenta
+	AuthEventIdentityLinked AuthEventType = "identity_linked"
+)
+
+// AuthOutcome es el resultado de la operación que registra un evento
+type AuthOutcome string
+
+const (
+	AuthOutcomeSuccess AuthOutcome = "success"
+	AuthOutcomeFailure AuthOutcome = "failure"
+	// AuthOutcomeMFARequired indica un primer factor correcto pendiente del segundo
+	AuthOutcomeMFARequired AuthOutcome = "mfa_required"
+)
+
+// AuthEvent representa un evento de auditoría de autenticación
+type AuthEvent struct {
+	// ID lo asigna el almacén al persistir el evento
+	ID     int64         `json:"id"`
+	Type   AuthEventType `json:"type" example:"login"`
+	UserID string        `json:"user_id,omitempty"`
+	// Identifier es el email, nick, teléfono o proveedor con el que se intentó la operación
+	Identifier string `json:"identifier,omitempty" example:"john@example.com"`
+	IP         string `json:"ip,omitempty" example:"203.0.113.7"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	// Outcome está vacío en los eventos que no corresponden a una operación (p. ej. account_locked)
+	Outcome AuthOutcome `json:"outcome,omitempty" example:"failure"`
+	Reason  string      `json:"reason,omitempty" example:"invalid credentials"`
+	// CorrelationID es el X-Request-ID de la petición que originó el evento
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+}
+
+// AuthEventQuery son los filtros del listado de eventos de auditoría; todos son opcionales
+type AuthEventQuery struct {
+	Type          AuthEventType `form:"type"`
+	UserID        string        `form:"user_id"`
+	Identifier    string        `form:"identifier"`
+	IP            string        `form:"ip"`
+	Outcome       AuthOutcome   `form:"outcome"`
+	CorrelationID string        `form:"correlation_id"`
+	// From y To acotan occurred_at (RFC 3339); From incluido, To excluido
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Cursor es el next_cursor de la página anterior
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// AuthEventFilter es la consulta ya validada que se envía al almacén de eventos
+type AuthEventFilter struct {
+	Type          AuthEventType
+	UserID        string
+	Identifier    string
+	IP            string
+	Outcome       AuthOutcome
+	CorrelationID string
+	From          time.Time
+	To            time.Time
+	// BeforeID retorna solo eventos anteriores a ese ID; 0 empieza por el más reciente
+	BeforeID int64
+	Limit    int
+}
+
+// AuthEventsResponse es una página de eventos de auditoría, del más reciente al más antiguo
+type AuthEventsResponse struct {
+	Events []*AuthEvent `json:"events"`
+	// NextCursor se envía como cursor para obtener la página siguiente; vacío en la última
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type correlationIDContextKey struct{}
+
+// WithCorrelationID retorna un contexto que lleva el identificador de la petición
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, correlationID)
+}
+
+// CorrelationIDFromContext retorna el identificador guardado con WithCorrelationID; vacío si no hay
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDContextKey{}).(string)
+	return correlationID
+}
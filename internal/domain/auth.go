@@ -6,10 +6,13 @@ type LoginRequest struct {
 	Password        string `json:"password" binding:"required,min=6" example:"T3st123@"`
 }
 
-// LoginResponse representa la respuesta exitosa de login
+// LoginResponse representa la respuesta exitosa de login.
+// Si el usuario tiene un segundo factor no se emiten tokens: MFARequired indica que hay que completar el challenge en /login/mfa.
 type LoginResponse struct {
-	User   *UserInfo  `json:"user"`
-	Tokens *TokenPair `json:"tokens"`
+	User        *UserInfo     `json:"user,omitempty"`
+	Tokens      *TokenPair    `json:"tokens,omitempty"`
+	MFARequired bool          `json:"mfa_required,omitempty"`
+	MFA         *MFAChallenge `json:"mfa,omitempty"`
 }
 
 // UserInfo representa la información del usuario en la respuesta
@@ -43,3 +46,32 @@ type RefreshRequest struct {
 type RefreshResponse struct {
 	Tokens *TokenPair `json:"tokens"`
 }
+
+// LogoutRequest representa la solicitud de logout.
+// El refresh token es opcional; si se envía se revoca también su sesión.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// IntrospectionRequest representa la solicitud de introspección de token (RFC 7662)
+type IntrospectionRequest struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+}
+
+// IntrospectionResponse representa la respuesta de introspección de token (RFC 7662).
+// Un token inactivo solo informa "active": false, sin detallar el motivo.
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
@@ -9,6 +9,9 @@ var (
 	// ErrUserNotFound se retorna cuando el usuario no existe
 	ErrUserNotFound = errors.New("user not found")
 
+	// ErrUserAlreadyExists se retorna cuando ya existe un usuario con el mismo email
+	ErrUserAlreadyExists = errors.New("user already exists")
+
 	// ErrUserInactive se retorna cuando el usuario está inactivo
 	ErrUserInactive = errors.New("user is inactive")
 
@@ -21,9 +24,127 @@ var (
 	// ErrTokenMalformed se retorna cuando el token está mal formado
 	ErrTokenMalformed = errors.New("token is malformed")
 
+	// ErrInvalidTokenType se retorna cuando el tipo del token no corresponde con la operación
+	ErrInvalidTokenType = errors.New("invalid token type")
+
+	// ErrInvalidTokenAudience se retorna cuando la audiencia del token no es la esperada
+	ErrInvalidTokenAudience = errors.New("invalid token audience")
+
+	// ErrRefreshTokenNotFound se retorna cuando el refresh token no está registrado
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	// ErrRefreshTokenReused se retorna cuando se presenta un refresh token que ya fue rotado
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+	// ErrTokenRevoked se retorna cuando el token fue revocado (logout)
+	ErrTokenRevoked = errors.New("token has been revoked")
+
+	// ErrSigningKeyNotFound se retorna cuando no existe una clave vigente con el kid del token
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+
+	// ErrInvalidClient se retorna cuando las credenciales del cliente (resource server) son inválidas
+	ErrInvalidClient = errors.New("invalid client credentials")
+
+	// ErrInvalidIDToken se retorna cuando el ID token del proveedor externo no es válido
+	ErrInvalidIDToken = errors.New("invalid id token")
+
+	// ErrIdentityEmailNotVerified se retorna cuando el proveedor no ha verificado el email de la identidad
+	ErrIdentityEmailNotVerified = errors.New("identity email is not verified")
+
+	// ErrUnsupportedProvider se retorna cuando el proveedor de identidad no está configurado
+	ErrUnsupportedProvider = errors.New("unsupported identity provider")
+
+	// ErrIdentityNotFound se retorna cuando la identidad externa no está vinculada a ningún usuario
+	ErrIdentityNotFound = errors.New("identity not found")
+
+	// ErrIdentityAlreadyLinked se retorna cuando la identidad o el proveedor ya están vinculados
+	ErrIdentityAlreadyLinked = errors.New("identity is already linked")
+
+	// ErrIdentityLinkRequired se retorna cuando ya existe una cuenta con el email de la identidad pero
+	// no se puede vincular automáticamente porque su email no está verificado
+	ErrIdentityLinkRequired = errors.New("an account with this email already exists, sign in to link the identity")
+
+	// ErrLastLoginMethod se retorna cuando desvincular dejaría la cuenta sin forma de iniciar sesión
+	ErrLastLoginMethod = errors.New("cannot remove the last login method of the account")
+
+	// ErrEmailDomainNotAllowed se retorna cuando el dominio del email no permite crear cuentas automáticamente
+	ErrEmailDomainNotAllowed = errors.New("email domain is not allowed")
+
+	// ErrOneTimeTokenInvalid se retorna cuando un token de un solo uso no existe, expiró o ya fue usado
+	ErrOneTimeTokenInvalid = errors.New("token is invalid, expired or already used")
+
+	// ErrMagicLinkBrowserMismatch se retorna cuando el enlace de login se abre sin la cookie del navegador que lo solicitó
+	ErrMagicLinkBrowserMismatch = errors.New("magic link must be opened in the browser that requested it")
+
+	// ErrEmailNotVerified se retorna cuando se exige email verificado para iniciar sesión
+	ErrEmailNotVerified = errors.New("email is not verified")
+
+	// ErrPasswordAlreadySet se retorna al intentar establecer la primera contraseña de una cuenta que ya tiene una
+	ErrPasswordAlreadySet = errors.New("password is already set")
+
+	// ErrPasswordNotSet se retorna al intentar cambiar la contraseña de una cuenta que no tiene
+	ErrPasswordNotSet = errors.New("password is not set")
+
+	// ErrReauthenticationRequired se retorna cuando la operación exige una autenticación reciente
+	ErrReauthenticationRequired = errors.New("recent authentication required")
+
+	// ErrAccountLocked se retorna (envuelto en AccountLockedError) cuando la cuenta está bloqueada por intentos fallidos
+	ErrAccountLocked = errors.New("account is temporarily locked")
+
+	// ErrMFANotEnrolled se retorna al confirmar un segundo factor sin haber iniciado el registro
+	ErrMFANotEnrolled = errors.New("mfa enrollment not found")
+
+	// ErrMFAAlreadyEnabled se retorna al registrar un segundo factor cuando ya hay uno activo
+	ErrMFAAlreadyEnabled = errors.New("mfa is already enabled")
+
+	// ErrMFANotEnabled se retorna al operar sobre el segundo factor de una cuenta que no lo tiene activo
+	ErrMFANotEnabled = errors.New("mfa is not enabled")
+
+	// ErrInvalidMFACode se retorna cuando el código TOTP o de recuperación no es válido o ya se usó
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+
+	// ErrPasskeyNotFound se retorna cuando la passkey no existe o no pertenece al usuario
+	ErrPasskeyNotFound = errors.New("passkey not found")
+
+	// ErrPasskeyAlreadyRegistered se retorna al registrar una credencial que ya está registrada
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+
+	// ErrInvalidPasskey se retorna cuando la aserción de una passkey no es válida
+	ErrInvalidPasskey = errors.New("invalid passkey assertion")
+
+	// ErrMFAFactorNotAllowed se retorna al completar un challenge MFA con el mismo método usado como primer factor
+	ErrMFAFactorNotAllowed = errors.New("mfa factor not allowed for this challenge")
+
+	// ErrWebAuthnSessionNotFound se retorna cuando la ceremonia WebAuthn no existe, expiró o ya se completó
+	ErrWebAuthnSessionNotFound = errors.New("webauthn session not found or expired")
+
+	// ErrInvalidPhoneNumber se retorna cuando un teléfono no está en formato internacional E.164
+	ErrInvalidPhoneNumber = errors.New("phone number must be in international format, e.g. +34600000000")
+
+	// ErrPhoneNumberAlreadyRegistered se retorna al guardar un teléfono que ya usa otra cuenta
+	ErrPhoneNumberAlreadyRegistered = errors.New("phone number is already registered")
+
+	// ErrNickNameTaken se retorna al guardar un nick_name que ya usa otra cuenta
+	ErrNickNameTaken = errors.New("nick name is already taken")
+
+	// ErrInvalidSMSCode se retorna cuando el código SMS no es válido, expiró o agotó sus intentos
+	ErrInvalidSMSCode = errors.New("invalid or expired sms code")
+
+	// ErrSessionNotFound se retorna cuando la sesión no existe, ya se cerró o no pertenece al usuario
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrWebhookSubscriptionNotFound se retorna cuando la suscripción de webhook no existe
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+
+	// ErrWebhookDeliveryNotFound se retorna cuando la entrega de webhook no existe
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
 	// ErrUnauthorized se retorna cuando no hay autorización
 	ErrUnauthorized = errors.New("unauthorized")
 
+	// ErrForbidden se retorna cuando el usuario autenticado no tiene permisos suficientes
+	ErrForbidden = errors.New("forbidden")
+
 	// ErrUserServiceUnavailable se retorna cuando el servicio de usuarios no está disponible
 	ErrUserServiceUnavailable = errors.New("user service unavailable")
 
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// ProviderGoogle identifica al proveedor de identidad de Google
+const ProviderGoogle = "google"
+
+// ExternalIdentity representa la identidad verificada que un proveedor OAuth/OIDC
+// afirma en su ID token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+// UserIdentity representa el vínculo entre una cuenta de proveedor externo y un usuario
+type UserIdentity struct {
+	Provider        string    `json:"provider"`
+	ProviderSubject string    `json:"provider_subject"`
+	UserID          string    `json:"user_id"`
+	EmailAtLink     string    `json:"email_at_link"`
+	LinkedAt        time.Time `json:"linked_at"`
+}
+
+// LinkIdentityRequest representa la solicitud para vincular un proveedor a la cuenta autenticada
+type LinkIdentityRequest struct {
+	Provider string `json:"provider" binding:"required" example:"google"`
+	IDToken  string `json:"id_token" binding:"required"`
+}
+
+// UserIdentitiesResponse representa la lista de proveedores vinculados a la cuenta
+type UserIdentitiesResponse struct {
+	Identities []*UserIdentity `json:"identities"`
+}
@@ -1,15 +1,29 @@
 package domain
 
 import (
+	"slices"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // JWTClaims representa los claims personalizados del JWT
 type JWTClaims struct {
-	UserID   string `json:"sub"`
-	Email    string `json:"email"`
-	NickName string `json:"nick_name"`
-	Role     string `json:"role"`
+	UserID        string    `json:"sub"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	NickName      string    `json:"nick_name"`
+	Role          string    `json:"role"`
+	TokenType     TokenType `json:"token_type"`
+	Scope         string    `json:"scope,omitempty"`
+	ClientID      string    `json:"client_id,omitempty"`
+	// AuthTime es el momento en que el usuario se autenticó; se conserva al refrescar los tokens
+	AuthTime int64 `json:"auth_time,omitempty"`
+	// AMR son los métodos con los que se autenticó el usuario (RFC 8176) y ACR el nivel resultante
+	AMR []string `json:"amr,omitempty"`
+	ACR string   `json:"acr,omitempty"`
+	// SessionID identifica la sesión del servidor a la que pertenece el token
+	SessionID string `json:"sid,omitempty"`
 	// Campos estándar de JWT
 	ExpiresAt int64  `json:"exp,omitempty"`
 	IssuedAt  int64  `json:"iat,omitempty"`
@@ -19,12 +33,78 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
+// Métodos de autenticación (claim amr, RFC 8176)
+const (
+	AMRPassword = "pwd"
+	// AMRFederated identifica el login con un proveedor de identidad externo
+	AMRFederated = "fed"
+	// AMROTP cubre los códigos TOTP y los códigos de recuperación
+	AMROTP = "otp"
+	// AMREmail identifica el login con un enlace enviado por email
+	AMREmail = "email"
+	// AMRSMS identifica el login con un código enviado por SMS
+	AMRSMS = "sms"
+	// AMRHardwareKey cubre las passkeys (prueba de posesión de una clave WebAuthn)
+	AMRHardwareKey = "hwk"
+	AMRMFA         = "mfa"
+)
+
+// Niveles de autenticación (claim acr)
+const (
+	ACRSingleFactor = "aal1"
+	ACRMultiFactor  = "aal2"
+)
+
+// AuthContext describe cómo y cuándo se autenticó el usuario al que se emiten los tokens
+type AuthContext struct {
+	AuthTime time.Time
+	// Methods son los valores amr de los factores verificados
+	Methods []string
+	// SessionID es la sesión a la que pertenecen los tokens; vacío hasta que se inicia la sesión
+	SessionID string
+}
+
+// WithMethods retorna una copia del contexto con los métodos añadidos
+func (a AuthContext) WithMethods(methods ...string) AuthContext {
+	combined := make([]string, 0, len(a.Methods)+len(methods))
+	combined = append(combined, a.Methods...)
+	for _, method := range methods {
+		if !slices.Contains(combined, method) {
+			combined = append(combined, method)
+		}
+	}
+	a.Methods = combined
+	return a
+}
+
+// ACR retorna el nivel de autenticación correspondiente a los métodos verificados
+func (a AuthContext) ACR() string {
+	if len(a.Methods) == 0 {
+		return ""
+	}
+	if slices.Contains(a.Methods, AMRMFA) {
+		return ACRMultiFactor
+	}
+	return ACRSingleFactor
+}
+
+// AuthContextFromClaims reconstruye el contexto de autenticación de un token ya emitido
+func AuthContextFromClaims(claims *JWTClaims) AuthContext {
+	authTime := claims.AuthTime
+	if authTime == 0 {
+		authTime = claims.IssuedAt
+	}
+	return AuthContext{AuthTime: time.Unix(authTime, 0), Methods: claims.AMR, SessionID: claims.SessionID}
+}
+
 // TokenType representa el tipo de token
 type TokenType string
 
 const (
 	AccessToken  TokenType = "access"
 	RefreshToken TokenType = "refresh"
+	// MFAChallengeToken solo sirve para completar el login con el segundo factor
+	MFAChallengeToken TokenType = "mfa_challenge"
 )
 
 // TokenPair representa un par de tokens (access y refresh)
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginAttempts representa los intentos fallidos de login de un usuario
+type LoginAttempts struct {
+	UserID       string
+	FailedCount  int
+	LastFailedAt time.Time
+	// LockedUntil es nil si la cuenta no está bloqueada
+	LockedUntil *time.Time
+}
+
+// AccountLockedError indica que la cuenta está bloqueada temporalmente y cuándo se puede reintentar
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrAccountLocked.Error(), e.RetryAfter.Round(time.Second))
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Factores con los que se puede completar un challenge MFA
+const (
+	FactorTOTP         = "totp"
+	FactorRecoveryCode = "recovery_code"
+)
+
+// RecoveryCodeCount es el número de códigos de recuperación generados en cada lote
+const RecoveryCodeCount = 10
+
+// MFAFactor representa el segundo factor TOTP de un usuario.
+// El secreto se guarda cifrado y los códigos de recuperación solo como hash.
+type MFAFactor struct {
+	UserID             string
+	EncryptedSecret    string
+	Enabled            bool
+	RecoveryCodeHashes []string
+	// LastUsedStep es el último intervalo TOTP aceptado; impide reutilizar un código
+	LastUsedStep int64
+	CreatedAt    time.Time
+	ConfirmedAt  *time.Time
+}
+
+// MFAStatusResponse representa el estado del segundo factor del usuario
+type MFAStatusResponse struct {
+	// Enabled indica si TOTP está activo
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+	// Passkeys es el número de passkeys registradas, que también sirven como segundo factor
+	Passkeys int `json:"passkeys"`
+}
+
+// MFAEnrollmentResponse contiene los datos para registrar el secreto TOTP en la app de autenticación
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/Bikes2Road:john@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Bikes2Road"`
+	// QRCode es el otpauth URI como imagen PNG en un data URI
+	QRCode string `json:"qr_code" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+// MFACodeRequest representa una solicitud que exige un código TOTP (o de recuperación, si se indica)
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// MFARecoveryCodesResponse contiene los códigos de recuperación en claro; solo se muestran una vez
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallenge es el challenge que se devuelve en el login cuando el usuario tiene un segundo factor
+type MFAChallenge struct {
+	// ChallengeToken es de un solo uso y solo sirve para POST /login/mfa
+	ChallengeToken string   `json:"challenge_token"`
+	Factors        []string `json:"factors" example:"totp,recovery_code,webauthn"`
+	ExpiresIn      int64    `json:"expires_in"` // Segundos hasta expiración
+}
+
+// MFALoginRequest representa la solicitud que completa el login con el segundo factor
+type MFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Code es un código TOTP o de recuperación
+	Code string `json:"code" binding:"required_without=Credential" example:"123456"`
+	// SessionID y Credential completan el challenge con una passkey (ver POST /login/mfa/webauthn)
+	SessionID  string          `json:"session_id,omitempty" binding:"required_with=Credential"`
+	Credential json.RawMessage `json:"credential,omitempty" binding:"required_without=Code" swaggertype:"object"`
+}
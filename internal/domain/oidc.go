@@ -0,0 +1,33 @@
+package domain
+
+// OpenIDConfiguration representa el documento de descubrimiento de OpenID Connect.
+// Solo anuncia lo que el servicio implementa: no hay authorization ni token endpoint OAuth 2.0
+// (el login es JSON), ni revocación RFC 7009, ni logout iniciado por el RP, ni ID tokens.
+// Los endpoints propios usan nombres no registrados que los clientes estándar ignoran.
+type OpenIDConfiguration struct {
+	Issuer                string   `json:"issuer"`
+	JWKSURI               string   `json:"jwks_uri"`
+	UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+	IntrospectionEndpoint string   `json:"introspection_endpoint"`
+	LoginEndpoint         string   `json:"login_endpoint"`
+	RefreshEndpoint       string   `json:"refresh_endpoint"`
+	LogoutEndpoint        string   `json:"logout_endpoint"`
+	SubjectTypesSupported []string `json:"subject_types_supported"`
+	ScopesSupported       []string `json:"scopes_supported"`
+	ClaimsSupported       []string `json:"claims_supported"`
+	ACRValuesSupported    []string `json:"acr_values_supported"`
+	// Métodos con los que los resource servers se autentican en el endpoint de introspección
+	IntrospectionEndpointAuthMethodsSupported []string `json:"introspection_endpoint_auth_methods_supported"`
+}
+
+// OIDCUserInfo representa la respuesta del endpoint userinfo con los claims estándar de OpenID Connect
+type OIDCUserInfo struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+	PreferredUsername string `json:"preferred_username"`
+	Role              string `json:"role"`
+	HasPassword       bool   `json:"has_password"`
+}
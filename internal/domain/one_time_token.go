@@ -0,0 +1,92 @@
+package domain
+
+import "time"
+
+// OneTimeTokenPurpose identifica para qué se emitió un token de un solo uso
+type OneTimeTokenPurpose string
+
+const (
+	// PurposeEmailVerification se usa en los enlaces de verificación de email
+	PurposeEmailVerification OneTimeTokenPurpose = "email_verification"
+	// PurposePasswordReset se usa en los enlaces de restablecimiento de contraseña
+	PurposePasswordReset OneTimeTokenPurpose = "password_reset"
+	// PurposeMagicLink se usa en los enlaces de login sin contraseña
+	PurposeMagicLink OneTimeTokenPurpose = "magic_link"
+)
+
+// OneTimeToken representa un token de un solo uso enviado al usuario (p. ej. por email).
+// Solo se persiste el hash del valor enviado.
+type OneTimeToken struct {
+	ID         string
+	Purpose    OneTimeTokenPurpose
+	TokenHash  string
+	UserID     string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	ConsumedAt *time.Time
+}
+
+// EmailMessage representa un email a enviar
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailVerificationRequest representa la solicitud de verificación de email
+type EmailVerificationRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// ResendVerificationRequest representa la solicitud de reenvío del email de verificación
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPasswordRequest representa la solicitud de recuperación de contraseña
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest representa la solicitud de restablecimiento de contraseña
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required" example:"N3wPass@"`
+}
+
+// MagicLinkRequest representa la solicitud de un enlace de login por email
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MagicLinkVerifyRequest representa el consumo de un enlace de login
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// ChangePasswordRequest representa la solicitud de cambio de contraseña del usuario autenticado
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required" example:"N3wPass@"`
+	// LogoutOtherSessions cierra el resto de sesiones y devuelve tokens nuevos para la actual
+	LogoutOtherSessions bool `json:"logout_other_sessions"`
+}
+
+// SetPasswordRequest representa la solicitud de primera contraseña de un usuario que solo usa OAuth
+type SetPasswordRequest struct {
+	Password string `json:"password" binding:"required" example:"N3wPass@"`
+	// LogoutOtherSessions cierra el resto de sesiones y devuelve tokens nuevos para la actual
+	LogoutOtherSessions bool `json:"logout_other_sessions"`
+}
+
+// PasswordChangeResponse representa la respuesta de cambio de contraseña.
+// Tokens solo se incluye si se cerraron las demás sesiones.
+type PasswordChangeResponse struct {
+	Message string     `json:"message"`
+	Tokens  *TokenPair `json:"tokens,omitempty"`
+}
+
+// MessageResponse representa una respuesta que solo informa un mensaje
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FactorWebAuthn identifica una passkey como factor para completar un challenge MFA
+const FactorWebAuthn = "webauthn"
+
+// WebAuthnCeremony identifica para qué se inició una ceremonia WebAuthn
+type WebAuthnCeremony string
+
+const (
+	// WebAuthnRegistration registra una nueva passkey en la cuenta autenticada
+	WebAuthnRegistration WebAuthnCeremony = "registration"
+	// WebAuthnLogin inicia sesión sin contraseña con una passkey detectable
+	WebAuthnLogin WebAuthnCeremony = "login"
+	// WebAuthnMFA completa el challenge MFA de un usuario con una de sus passkeys
+	WebAuthnMFA WebAuthnCeremony = "mfa"
+)
+
+// Passkey representa una credencial WebAuthn registrada por un usuario
+type Passkey struct {
+	ID           string
+	UserID       string
+	CredentialID []byte
+	// PublicKey es la clave pública en formato COSE
+	PublicKey       []byte
+	AttestationType string
+	Transports      []string
+	// SignCount es el último contador de firmas visto; si no avanza el autenticador puede estar clonado
+	SignCount      uint32
+	AAGUID         []byte
+	BackupEligible bool
+	BackupState    bool
+	Name           string
+	CreatedAt      time.Time
+	LastUsedAt     *time.Time
+}
+
+// WebAuthnSession guarda los datos de una ceremonia en curso entre el begin y el finish
+type WebAuthnSession struct {
+	ID       string
+	Ceremony WebAuthnCeremony
+	// UserID es vacío en el login sin contraseña, donde el usuario se conoce al terminar la ceremonia
+	UserID    string
+	Data      []byte
+	ExpiresAt time.Time
+}
+
+// PasskeyAssertion es el resultado de una ceremonia de autenticación verificada
+type PasskeyAssertion struct {
+	UserID string
+	// UserVerified indica que el autenticador verificó al usuario (PIN o biometría), no solo su presencia
+	UserVerified bool
+}
+
+// PasskeyInfo representa una passkey en las respuestas de la API
+type PasskeyInfo struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name" example:"iPhone"`
+	Transports []string   `json:"transports" example:"internal,hybrid"`
+	Synced     bool       `json:"synced"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// PasskeysResponse contiene las passkeys registradas por el usuario
+type PasskeysResponse struct {
+	Passkeys []PasskeyInfo `json:"passkeys"`
+}
+
+// WebAuthnOptionsResponse contiene las opciones para navigator.credentials.create/get y la sesión que las acompaña
+type WebAuthnOptionsResponse struct {
+	SessionID string `json:"session_id"`
+	// Options es el objeto publicKey de la especificación WebAuthn
+	Options json.RawMessage `json:"options" swaggertype:"object"`
+}
+
+// PasskeyRegistrationRequest representa la respuesta del autenticador al crear una passkey
+type PasskeyRegistrationRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+	// Name identifica la passkey en el listado; por defecto "Passkey"
+	Name       string          `json:"name" example:"iPhone"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// PasskeyAssertionRequest representa la respuesta del autenticador en una ceremonia de login
+type PasskeyAssertionRequest struct {
+	SessionID  string          `json:"session_id" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// MFAPasskeyRequest inicia la verificación del challenge MFA con una passkey
+type MFAPasskeyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit define un token bucket: admite ráfagas de hasta Burst peticiones y recarga Burst tokens cada Period
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Enabled indica si el límite está configurado
+func (l RateLimit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// RateLimitResult es el resultado de consumir un token de un bucket
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter es el tiempo hasta que el bucket vuelve a estar lleno
+	ResetAfter time.Duration
+	// RetryAfter es el tiempo hasta que haya un token disponible; cero si la petición se admitió
+	RetryAfter time.Duration
+}
+
+// Take recarga el bucket con los tokens acumulados durante elapsed e intenta consumir uno.
+// Retorna los tokens que quedan en el bucket y el resultado de la petición.
+func (l RateLimit) Take(tokens float64, elapsed time.Duration) (float64, RateLimitResult) {
+	perSecond := float64(l.Burst) / l.Period.Seconds()
+	available := math.Min(float64(l.Burst), tokens+elapsed.Seconds()*perSecond)
+
+	result := RateLimitResult{Limit: l.Burst}
+	if available >= 1 {
+		available--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - available) / perSecond)
+	}
+
+	result.Remaining = int(math.Floor(available))
+	result.ResetAfter = secondsToDuration((float64(l.Burst) - available) / perSecond)
+	return available, result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
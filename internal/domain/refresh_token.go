@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// RefreshTokenRecord representa un refresh token persistido en el servidor.
+// Solo se guarda el hash del token; los tokens de una misma sesión comparten FamilyID.
+type RefreshTokenRecord struct {
+	ID        string
+	FamilyID  string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+// IsRotated indica si el token ya fue intercambiado por uno nuevo
+func (t *RefreshTokenRecord) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
+// IsRevoked indica si el token fue revocado
+func (t *RefreshTokenRecord) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
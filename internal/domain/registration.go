@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"unicode"
+)
+
+// ErrValidation se retorna (envuelto en ValidationError) cuando uno o más campos de la solicitud no son válidos
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError contiene los errores de validación por campo
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// NewValidationError crea un ValidationError vacío
+func NewValidationError() *ValidationError {
+	return &ValidationError{Fields: make(map[string]string)}
+}
+
+// Add registra el error de un campo; solo se conserva el primero de cada campo
+func (e *ValidationError) Add(field, message string) {
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+}
+
+// HasErrors indica si algún campo tiene errores
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+func (e *ValidationError) Error() string {
+	return ErrValidation.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// RegisterRequest representa la solicitud de registro de un usuario.
+// La validación se hace en el servicio para poder informar los errores por campo.
+type RegisterRequest struct {
+	Email       string `json:"email" example:"john@example.com"`
+	NickName    string `json:"nick_name" example:"johndoe"`
+	FirstName   string `json:"first_name" example:"John"`
+	LastName    string `json:"last_name" example:"Doe"`
+	PhoneNumber string `json:"phone_number,omitempty" example:"+34600000000"`
+	Password    string `json:"password" example:"T3st123@"`
+}
+
+// RegisterResponse representa la respuesta de registro.
+// Tokens solo se incluye si el servicio está configurado para iniciar sesión tras el registro.
+type RegisterResponse struct {
+	User   *UserInfo  `json:"user"`
+	Tokens *TokenPair `json:"tokens,omitempty"`
+}
+
+// ValidatePassword verifica que la contraseña cumple la política descrita en ErrPasswordInvalid
+func ValidatePassword(password string) error {
+	if len(password) < 8 {
+		return ErrPasswordInvalid
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSpecial = true
+		}
+	}
+
+	if !hasUpper || !hasLower || !hasDigit || !hasSpecial {
+		return ErrPasswordInvalid
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Session representa un inicio de sesión de un usuario en un dispositivo.
+// Su ID es el claim sid de los tokens y la familia de sus refresh tokens.
+type Session struct {
+	ID        string
+	UserID    string
+	IP        string
+	UserAgent string
+	// Methods son los valores amr con los que se inició la sesión
+	Methods    []string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	// ExpiresAt es la expiración del último refresh token emitido para la sesión
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// IsRevoked indica si la sesión fue cerrada
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// SessionInfo representa una sesión en las respuestas de la API
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	IP         string    `json:"ip" example:"203.0.113.7"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"`
+	AMR        []string  `json:"amr" example:"pwd,otp,mfa"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current indica la sesión del token con el que se hace la petición
+	Current bool `json:"current"`
+}
+
+// SessionsResponse contiene las sesiones activas del usuario
+type SessionsResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
+
+// ClientInfo identifica el dispositivo desde el que llega una petición
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type clientInfoContextKey struct{}
+
+// WithClientInfo retorna un contexto que lleva los datos del cliente de la petición
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// ClientInfoFromContext retorna los datos del cliente guardados con WithClientInfo; vacíos si no hay
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoContextKey{}).(ClientInfo)
+	return info
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// SigningKeyStatus representa el estado de una clave del keyring
+type SigningKeyStatus string
+
+const (
+	// SigningKeyActive firma los nuevos tokens
+	SigningKeyActive SigningKeyStatus = "active"
+	// SigningKeyRetired ya no firma pero verifica tokens hasta su fecha límite
+	SigningKeyRetired SigningKeyStatus = "retired"
+)
+
+// SigningKey representa una clave de firma de tokens identificada por su kid
+type SigningKey struct {
+	Kid         string           `json:"kid"`
+	Algorithm   string           `json:"algorithm"`
+	Status      SigningKeyStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	RetiredAt   *time.Time       `json:"retired_at,omitempty"`
+	VerifyUntil *time.Time       `json:"verify_until,omitempty"`
+	// PrivateKey firma y PublicKey verifica; en HS256 ambas son el secreto compartido
+	PrivateKey any `json:"-"`
+	PublicKey  any `json:"-"`
+}
+
+// CanVerify indica si la clave todavía puede verificar tokens en el instante dado
+func (k *SigningKey) CanVerify(at time.Time) bool {
+	return k.VerifyUntil == nil || at.Before(*k.VerifyUntil)
+}
+
+// IsSymmetric indica si la clave es un secreto compartido que nunca debe publicarse
+func (k *SigningKey) IsSymmetric() bool {
+	return k.Algorithm == "HS256"
+}
+
+// SigningKeyRecord es el estado de una clave del keyring compartido por todas las réplicas
+type SigningKeyRecord struct {
+	Kid       string
+	Algorithm string
+	Status    SigningKeyStatus
+	// EncryptedKey es el material privado cifrado de las claves generadas por rotación;
+	// vacío en las claves de la configuración, que cada réplica carga de sus variables de entorno
+	EncryptedKey string
+	CreatedAt    time.Time
+	RetiredAt    *time.Time
+	VerifyUntil  *time.Time
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// e164Pattern admite un + seguido del código de país y el número, hasta 15 dígitos en total
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// NormalizePhoneNumber convierte un teléfono en formato internacional a E.164.
+// Se eliminan los separadores habituales y el prefijo 00 se sustituye por +; los números sin código de país no son válidos.
+func NormalizePhoneNumber(phoneNumber string) (string, error) {
+	normalized := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phoneNumber))
+
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+
+	if !e164Pattern.MatchString(normalized) {
+		return "", ErrInvalidPhoneNumber
+	}
+	return normalized, nil
+}
+
+// SMSMessage representa un SMS a enviar
+type SMSMessage struct {
+	// To es el teléfono de destino en formato E.164
+	To   string
+	Body string
+}
+
+// PhoneOTP es el código de login enviado por SMS. Cada usuario tiene como mucho uno activo y solo se guarda su hash.
+type PhoneOTP struct {
+	UserID string
+	// PhoneNumber es el teléfono al que se envió; el código deja de valer si el usuario cambia de teléfono
+	PhoneNumber string
+	CodeHash    string
+	// Attempts cuenta las verificaciones intentadas con este código
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// SMSLoginRequest representa la solicitud de un código de login por SMS
+type SMSLoginRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" example:"+34600000000"`
+}
+
+// SMSLoginVerifyRequest representa la verificación del código de login recibido por SMS
+type SMSLoginVerifyRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required" example:"+34600000000"`
+	Code        string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
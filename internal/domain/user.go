@@ -2,6 +2,13 @@ package domain
 
 import "time"
 
+const (
+	// RoleUser es el rol por defecto de los usuarios
+	RoleUser = "user"
+	// RoleAdmin da acceso a los endpoints de administración
+	RoleAdmin = "admin"
+)
+
 // User representa la información básica del usuario necesaria para autenticación
 type UserAuth struct {
 	ID        string `json:"id"`
@@ -13,18 +20,19 @@ type UserAuth struct {
 }
 
 type User struct {
-	ID          string    `json:"id"`
-	NickName    string    `json:"nick_name"`
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
-	Email       string    `json:"email"`
-	PhoneNumber string    `json:"phone_number"`
-	Password    string    `json:"password"`
-	HasPassword bool      `json:"has_password"`
-	IsActive    bool      `json:"is_active"`
-	Role        string    `json:"role"`
-	DateCreated time.Time `json:"date_created"`
-	DateUpdated time.Time `json:"date_updated"`
+	ID            string    `json:"id"`
+	NickName      string    `json:"nick_name"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Email         string    `json:"email"`
+	PhoneNumber   string    `json:"phone_number"`
+	Password      string    `json:"password"`
+	HasPassword   bool      `json:"has_password"`
+	IsActive      bool      `json:"is_active"`
+	Role          string    `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	DateCreated   time.Time `json:"date_created"`
+	DateUpdated   time.Time `json:"date_updated"`
 }
 
 // IsValid verifica si el usuario es válido para autenticación
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// SecurityEventType identifica los eventos de seguridad que se notifican a otros servicios por webhook
+type SecurityEventType string
+
+const (
+	// SecurityEventNewDeviceLogin se emite cuando un usuario inicia sesión desde un dispositivo que no había usado
+	SecurityEventNewDeviceLogin SecurityEventType = "user.login.new_device"
+	// SecurityEventPasswordChanged se emite cuando un usuario cambia, establece o restablece su contraseña
+	SecurityEventPasswordChanged SecurityEventType = "user.password.changed"
+	// SecurityEventUserDeactivated se emite cuando un administrador desactiva una cuenta
+	SecurityEventUserDeactivated SecurityEventType = "user.deactivated"
+	// SecurityEventSessionsRevoked se emite cuando se cierran sesiones de un usuario, salvo con el logout de la propia sesión
+	SecurityEventSessionsRevoked SecurityEventType = "user.sessions.revoked"
+)
+
+// SecurityEventTypes son los eventos a los que se puede suscribir un webhook
+var SecurityEventTypes = []SecurityEventType{
+	SecurityEventNewDeviceLogin,
+	SecurityEventPasswordChanged,
+	SecurityEventUserDeactivated,
+	SecurityEventSessionsRevoked,
+}
+
+// SecurityEvent es el cuerpo JSON que reciben los webhooks
+type SecurityEvent struct {
+	// ID identifica el evento; se repite en los reintentos para que el receptor pueda descartar duplicados
+	ID         string            `json:"id"`
+	Type       SecurityEventType `json:"type" example:"user.password.changed"`
+	UserID     string            `json:"user_id"`
+	Data       map[string]string `json:"data,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// WebhookSubscription representa un endpoint que recibe eventos de seguridad
+type WebhookSubscription struct {
+	ID  string
+	URL string
+	// Secret firma las entregas; se guarda cifrado
+	Secret     string
+	EventTypes []SecurityEventType
+	CreatedAt  time.Time
+}
+
+// WebhookDeliveryStatus es el estado de una entrega en el outbox
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead indica que se agotaron los reintentos; solo se vuelve a enviar con un replay
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery representa el envío de un evento a una suscripción
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      SecurityEventType
+	// Payload es el cuerpo exacto que se firma y envía en cada intento
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	LastStatusCode int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookRequest es la petición firmada de un intento de entrega
+type WebhookRequest struct {
+	URL       string
+	EventID   string
+	EventType SecurityEventType
+	Timestamp int64
+	Signature string
+	Body      []byte
+}
+
+// CreateWebhookRequest representa el alta de una suscripción
+type CreateWebhookRequest struct {
+	URL        string              `json:"url" binding:"required,url" example:"https://bookings.bikes2road.com/webhooks/auth"`
+	EventTypes []SecurityEventType `json:"event_types" binding:"required,min=1" example:"user.password.changed,user.deactivated"`
+}
+
+// WebhookSubscriptionInfo representa una suscripción en las respuestas de la API, sin su secreto
+type WebhookSubscriptionInfo struct {
+	ID         string              `json:"id"`
+	URL        string              `json:"url"`
+	EventTypes []SecurityEventType `json:"event_types"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// WebhookSubscriptionCreated incluye el secreto de firma, que solo se muestra al crear la suscripción
+type WebhookSubscriptionCreated struct {
+	WebhookSubscriptionInfo
+	Secret string `json:"secret"`
+}
+
+// WebhookSubscriptionsResponse contiene las suscripciones registradas
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscriptionInfo `json:"subscriptions"`
+}
+
+// WebhookDeliveryQuery son los filtros del listado de entregas; todos son opcionales
+type WebhookDeliveryQuery struct {
+	Status         WebhookDeliveryStatus `form:"status"`
+	SubscriptionID string                `form:"subscription_id"`
+	Limit          int                   `form:"limit"`
+}
+
+// WebhookDeliveryInfo representa una entrega en las respuestas de la API
+type WebhookDeliveryInfo struct {
+	ID             string                `json:"id"`
+	SubscriptionID string                `json:"subscription_id"`
+	EventID        string                `json:"event_id"`
+	EventType      SecurityEventType     `json:"event_type"`
+	Status         WebhookDeliveryStatus `json:"status" example:"dead"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	LastError      string                `json:"last_error,omitempty"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveriesResponse contiene las entregas más recientes que cumplen los filtros
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryInfo `json:"deliveries"`
+}
+
+// SignWebhook calcula la firma de una entrega: HMAC-SHA256 con el secreto de la suscripción
+// sobre "<timestamp>.<body>", en hexadecimal y con el prefijo "sha256="
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature comprueba en tiempo constante la firma de una entrega recibida.
+// El receptor debe además rechazar los timestamps demasiado antiguos para evitar reenvíos.
+func VerifyWebhookSignature(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body)))
+}
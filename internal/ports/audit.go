@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+// AuditLogger define la interfaz para registrar eventos de auditoría de autenticación.
+// Los errores de escritura no se propagan para no interrumpir el flujo de autenticación.
+type AuditLogger interface {
+	Record(ctx context.Context, event domain.AuthEvent)
+}
+
+// BufferedAuditLogger es un AuditLogger que escribe en segundo plano y debe cerrarse al apagar el servicio
+type BufferedAuditLogger interface {
+	AuditLogger
+	// Close detiene el writer tras persistir los eventos pendientes. Los eventos registrados
+	// después se envían al logger de respaldo.
+	Close(ctx context.Context) error
+}
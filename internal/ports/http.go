@@ -7,12 +7,93 @@ import (
 // AuthHandler define la interfaz para los handlers de autenticación
 type AuthHandler interface {
 	Login(c *gin.Context)
+	LoginMFA(c *gin.Context)
+	BeginMFAPasskey(c *gin.Context)
+	BeginPasskeyLogin(c *gin.Context)
+	LoginPasskey(c *gin.Context)
+	RequestMagicLink(c *gin.Context)
+	LoginMagicLink(c *gin.Context)
+	RequestSMSCode(c *gin.Context)
+	LoginSMS(c *gin.Context)
 	OauthLogin(c *gin.Context)
+	Register(c *gin.Context)
 	Validate(c *gin.Context)
 	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	SetPassword(c *gin.Context)
+	UserInfo(c *gin.Context)
+	Introspect(c *gin.Context)
 }
 
 // HealthHandler define la interfaz para el handler de health check
 type HealthHandler interface {
 	Health(c *gin.Context)
 }
+
+// DiscoveryHandler define la interfaz para los endpoints públicos de descubrimiento (.well-known)
+type DiscoveryHandler interface {
+	JWKS(c *gin.Context)
+	OpenIDConfiguration(c *gin.Context)
+}
+
+// AdminHandler define la interfaz para los handlers de administración
+type AdminHandler interface {
+	ListSigningKeys(c *gin.Context)
+	RotateSigningKey(c *gin.Context)
+	UnlockUser(c *gin.Context)
+	DeactivateUser(c *gin.Context)
+	ListAuthEvents(c *gin.Context)
+}
+
+// WebhookHandler define la interfaz para los handlers de administración de webhooks
+type WebhookHandler interface {
+	CreateSubscription(c *gin.Context)
+	ListSubscriptions(c *gin.Context)
+	DeleteSubscription(c *gin.Context)
+	ListDeliveries(c *gin.Context)
+	ReplayDelivery(c *gin.Context)
+}
+
+// EmailHandler define la interfaz para los handlers de verificación de email
+type EmailHandler interface {
+	VerifyEmail(c *gin.Context)
+	ResendVerification(c *gin.Context)
+}
+
+// PasswordHandler define la interfaz para los handlers de recuperación y cambio de contraseña
+type PasswordHandler interface {
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+}
+
+// MFAHandler define la interfaz para los handlers del segundo factor
+type MFAHandler interface {
+	Status(c *gin.Context)
+	EnrollTOTP(c *gin.Context)
+	ConfirmTOTP(c *gin.Context)
+	Disable(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+}
+
+// PasskeyHandler define la interfaz para los handlers de gestión de passkeys
+type PasskeyHandler interface {
+	List(c *gin.Context)
+	BeginRegistration(c *gin.Context)
+	FinishRegistration(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
+// SessionHandler define la interfaz para los handlers de las sesiones del usuario
+type SessionHandler interface {
+	List(c *gin.Context)
+	Revoke(c *gin.Context)
+}
+
+// IdentityHandler define la interfaz para los handlers de identidades externas vinculadas
+type IdentityHandler interface {
+	ListIdentities(c *gin.Context)
+	LinkIdentity(c *gin.Context)
+	UnlinkIdentity(c *gin.Context)
+}
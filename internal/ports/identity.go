@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+// IdentityVerifier define la interfaz para verificar los ID tokens emitidos por proveedores externos
+type IdentityVerifier interface {
+	// Verify valida firma, emisor, audiencia y expiración del ID token y retorna la identidad que contiene
+	Verify(ctx context.Context, provider, idToken string) (*domain.ExternalIdentity, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+// Mailer define la interfaz para enviar emails a los usuarios
+type Mailer interface {
+	Send(ctx context.Context, message domain.EmailMessage) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+// PasswordHasher define la interfaz para generar y verificar hashes de contraseñas.
+// El algoritmo de cada hash se identifica por su prefijo, por lo que pueden convivir varios.
+type PasswordHasher interface {
+	// Hash genera el hash de la contraseña con el algoritmo y parámetros configurados
+	Hash(password string) (string, error)
+	// Verify comprueba la contraseña contra un hash de cualquiera de los algoritmos soportados
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash indica si el hash usa un algoritmo o parámetros distintos de los configurados
+	NeedsRehash(hash string) bool
+}
@@ -2,25 +2,37 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/bikes2road/authentication/internal/domain"
 )
 
+// Transactor runs several repository calls in a single database transaction.
+// Repositories called with the context passed to fn use that transaction; nested calls join it.
+type Transactor interface {
+	// WithinTx commits if fn returns nil and rolls back otherwise
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // UserRepository defines the interface for user data persistence
 // This is a port (output port) that will be implemented by adapters layer
 type UserRepository interface {
-	// Create persists a new user to the database
+	// Create persists a new user to the database.
+	// It returns domain.ErrUserAlreadyExists if the email is already registered.
 	Create(ctx context.Context, user *domain.User) error
 
 	// GetByID retrieves a user by their ID
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 
-	// GetByEmail retrieves a user by their email address
+	// GetByEmail retrieves a user by their email address, ignoring case
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 
-	// GetByEmailOrNickName retrieves a user by their email or nick name
+	// GetByEmailOrNickName retrieves a user by their email (ignoring case) or nick name
 	GetByEmailOrNickName(ctx context.Context, emailOrNickName string) (*domain.User, error)
 
+	// GetByPhoneNumber retrieves a user by their E.164 phone number
+	GetByPhoneNumber(ctx context.Context, phoneNumber string) (*domain.User, error)
+
 	// GetByNickName retrieves a user by their nick name
 	GetByNickName(ctx context.Context, nickName string) (*domain.User, error)
 
@@ -33,6 +45,263 @@ type UserRepository interface {
 	// Delete removes a user from the database
 	Delete(ctx context.Context, id string) error
 
-	// ExistsByEmail checks if a user with the given email exists
+	// ExistsByEmail checks if a user with the given email exists, ignoring case
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+
+	// ExistsByNickName checks if a user with the given nick name exists
+	ExistsByNickName(ctx context.Context, nickName string) (bool, error)
+
+	// ExistsByPhoneNumber checks if a user with the given phone number exists
+	ExistsByPhoneNumber(ctx context.Context, phoneNumber string) (bool, error)
+}
+
+// RefreshTokenRepository defines the interface for server-side refresh token persistence
+type RefreshTokenRepository interface {
+	// Create persists a newly issued refresh token
+	Create(ctx context.Context, token *domain.RefreshTokenRecord) error
+
+	// GetByHash retrieves a refresh token by the hash of its value
+	GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshTokenRecord, error)
+
+	// Rotate marks the current token as rotated and persists its replacement atomically.
+	// It returns domain.ErrRefreshTokenReused if the current token was already rotated or revoked.
+	Rotate(ctx context.Context, currentID string, next *domain.RefreshTokenRecord) error
+
+	// RevokeFamily revokes every token that belongs to the given family
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeAllForUser revokes every active refresh token of the given user
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
+
+// SigningKeyRepository defines the interface for the keyring state shared by every replica
+type SigningKeyRepository interface {
+	// Activate stores a key as the active one and retires the previous active keys until verifyUntil.
+	// A kid that is already stored keeps its state, so restarting with the same configuration changes nothing.
+	Activate(ctx context.Context, key *domain.SigningKeyRecord, verifyUntil time.Time) error
+
+	// Retire stores a key as retired until verifyUntil. A key that already has a deadline keeps it,
+	// so restarts do not extend the grace period.
+	Retire(ctx context.Context, key *domain.SigningKeyRecord, verifyUntil time.Time) error
+
+	// List retrieves every stored key, most recently created first
+	List(ctx context.Context) ([]*domain.SigningKeyRecord, error)
+
+	// DeleteExpired removes generated keys whose grace period has ended. Keys loaded from the
+	// configuration are kept so their deadline is not reset if they are still configured.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// SessionRepository defines the interface for the server-side sessions referenced by the sid claim
+type SessionRepository interface {
+	// Create persists a newly started session
+	Create(ctx context.Context, session *domain.Session) error
+
+	// Get retrieves a session by its ID. It returns domain.ErrSessionNotFound if it does not exist.
+	Get(ctx context.Context, id string) (*domain.Session, error)
+
+	// ListActiveByUser retrieves the sessions of the user that are neither revoked nor expired, most recently seen first
+	ListActiveByUser(ctx context.Context, userID string) ([]*domain.Session, error)
+
+	// Touch records a refresh of the session extending its expiration to the one of the new refresh token
+	Touch(ctx context.Context, id string, seenAt, expiresAt time.Time) error
+
+	// Revoke marks a session of the user as revoked.
+	// It returns domain.ErrSessionNotFound if the user has no such active session.
+	Revoke(ctx context.Context, userID, id string) error
+
+	// RevokeAllForUser marks every active session of the user as revoked
+	RevokeAllForUser(ctx context.Context, userID string) error
+
+	// IsKnownDevice reports whether the user already has a stored session from the user agent.
+	// Users without stored sessions have no device history, so any device is reported as known.
+	IsKnownDevice(ctx context.Context, userID, userAgent string) (bool, error)
+
+	// DeleteExpired removes sessions that have expired
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// TokenRevocationRepository defines the interface for the jti-based token revocation store
+type TokenRevocationRepository interface {
+	// Revoke stores the jti as revoked until the token's own expiration
+	Revoke(ctx context.Context, jti, userID string, expiresAt time.Time) error
+
+	// Consume revokes the jti of a single-use token in one conditional insert.
+	// It returns false if the jti was already revoked, i.e. the token was already used.
+	Consume(ctx context.Context, jti, userID string, expiresAt time.Time) (bool, error)
+
+	// RevokeAllForUser revokes every token of the user issued at or before issuedBefore
+	RevokeAllForUser(ctx context.Context, userID string, issuedBefore time.Time) error
+
+	// IsRevoked checks if a token was revoked by its jti or by a user-wide revocation
+	IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error)
+
+	// DeleteExpired removes revocation entries whose tokens have already expired
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// AuthEventRepository defines the interface for the persistent authentication audit log
+type AuthEventRepository interface {
+	// CreateBatch persists the given events in a single round trip
+	CreateBatch(ctx context.Context, events []domain.AuthEvent) error
+
+	// List retrieves the events matching the filter, newest first
+	List(ctx context.Context, filter domain.AuthEventFilter) ([]*domain.AuthEvent, error)
+}
+
+// WebhookRepository defines the interface for webhook subscriptions and the outbox of their deliveries
+type WebhookRepository interface {
+	// CreateSubscription persists a new subscription
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+
+	// ListSubscriptions retrieves every subscription, oldest first
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+
+	// GetSubscription retrieves a subscription by its ID.
+	// It returns domain.ErrWebhookSubscriptionNotFound if it does not exist.
+	GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+
+	// DeleteSubscription removes a subscription and its deliveries.
+	// It returns domain.ErrWebhookSubscriptionNotFound if it does not exist.
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// Enqueue atomically stores one pending delivery of the payload for every subscription to the event type,
+	// inside the transaction of the context when called from Transactor.WithinTx. It returns how many deliveries were stored.
+	Enqueue(ctx context.Context, event domain.SecurityEvent, payload []byte) (int, error)
+
+	// ClaimDue returns up to limit pending deliveries whose next attempt is due, hiding them from other
+	// claimers for the lease so that each delivery is attempted by a single replica at a time
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+
+	// UpdateAttempt stores the outcome of a delivery attempt: status, attempts, next attempt and last error
+	UpdateAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// ListDeliveries retrieves the deliveries matching the filters, newest first
+	ListDeliveries(ctx context.Context, status domain.WebhookDeliveryStatus, subscriptionID string, limit int) ([]*domain.WebhookDelivery, error)
+
+	// Replay resets a delivery to pending with no attempts so it is sent again as soon as possible.
+	// It returns domain.ErrWebhookDeliveryNotFound if it does not exist.
+	Replay(ctx context.Context, id string) error
+}
+
+// UserIdentityRepository defines the interface for links between external provider accounts and users
+type UserIdentityRepository interface {
+	// Create links an external identity to a user.
+	// It returns domain.ErrIdentityAlreadyLinked if the identity or the provider is already linked.
+	Create(ctx context.Context, identity *domain.UserIdentity) error
+
+	// GetByProviderSubject retrieves the identity linked to the given provider account
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+
+	// ListByUser retrieves every identity linked to the given user
+	ListByUser(ctx context.Context, userID string) ([]*domain.UserIdentity, error)
+
+	// Delete unlinks the given provider from the user
+	Delete(ctx context.Context, userID, provider string) error
+}
+
+// OneTimeTokenRepository defines the interface for hashed single-use tokens (email verification, password reset...)
+type OneTimeTokenRepository interface {
+	// Create persists a newly issued token
+	Create(ctx context.Context, token *domain.OneTimeToken) error
+
+	// Consume atomically marks the token as used and returns it.
+	// It returns domain.ErrOneTimeTokenInvalid if the token does not exist, has expired or was already used.
+	Consume(ctx context.Context, purpose domain.OneTimeTokenPurpose, tokenHash string) (*domain.OneTimeToken, error)
+
+	// InvalidateForUser consumes every pending token of the user for the given purpose
+	InvalidateForUser(ctx context.Context, userID string, purpose domain.OneTimeTokenPurpose) error
+
+	// DeleteExpired removes tokens that have expired
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// LoginAttemptRepository defines the interface for the per-account failed login counters used by the lockout
+type LoginAttemptRepository interface {
+	// Get retrieves the failed login state of the user, or nil if no failure is recorded
+	Get(ctx context.Context, userID string) (*domain.LoginAttempts, error)
+
+	// RecordFailure atomically increments the failure counter and returns the updated state.
+	// The counter restarts from one when the previous failure happened before resetBefore.
+	RecordFailure(ctx context.Context, userID string, resetBefore time.Time) (*domain.LoginAttempts, error)
+
+	// Lock locks the account until the given time, never shortening an existing lock
+	Lock(ctx context.Context, userID string, until time.Time) error
+
+	// Reset clears the failed login state of the user
+	Reset(ctx context.Context, userID string) error
+}
+
+// MFARepository defines the interface for the TOTP second factor of each user
+type MFARepository interface {
+	// SavePending stores a new, not yet confirmed, enrollment replacing any previous pending one.
+	// It returns domain.ErrMFAAlreadyEnabled if the user already has an enabled factor.
+	SavePending(ctx context.Context, factor *domain.MFAFactor) error
+
+	// Get retrieves the factor of the user. It returns domain.ErrMFANotEnrolled if there is none.
+	Get(ctx context.Context, userID string) (*domain.MFAFactor, error)
+
+	// Enable confirms the pending factor storing its recovery code hashes and the TOTP step used to confirm it
+	Enable(ctx context.Context, userID string, recoveryCodeHashes []string, step int64) error
+
+	// UseStep records a TOTP step as used. It returns false if the step is not newer than the last one used.
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+
+	// ConsumeRecoveryCode atomically removes the recovery code hash. It returns false if the code was not available.
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+
+	// ReplaceRecoveryCodes replaces every recovery code hash of an enabled factor
+	ReplaceRecoveryCodes(ctx context.Context, userID string, recoveryCodeHashes []string) error
+
+	// Delete removes the factor of the user
+	Delete(ctx context.Context, userID string) error
+}
+
+// PasskeyRepository defines the interface for the WebAuthn credentials registered by the users
+type PasskeyRepository interface {
+	// Create persists a newly registered passkey.
+	// It returns domain.ErrPasskeyAlreadyRegistered if the credential ID is already registered.
+	Create(ctx context.Context, passkey *domain.Passkey) error
+
+	// ListByUser retrieves every passkey of the user, oldest first
+	ListByUser(ctx context.Context, userID string) ([]*domain.Passkey, error)
+
+	// CountByUser returns how many passkeys the user has registered
+	CountByUser(ctx context.Context, userID string) (int, error)
+
+	// RecordUse stores the sign counter and backup state reported by the last successful assertion
+	RecordUse(ctx context.Context, id string, signCount uint32, backupState bool, usedAt time.Time) error
+
+	// Delete removes a passkey of the user. It returns domain.ErrPasskeyNotFound if the user has no such passkey.
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// WebAuthnSessionRepository defines the interface for the state of WebAuthn ceremonies between begin and finish
+type WebAuthnSessionRepository interface {
+	// Create persists the session of a ceremony that has just begun
+	Create(ctx context.Context, session *domain.WebAuthnSession) error
+
+	// Consume atomically deletes and returns the session so a ceremony can only be finished once.
+	// It returns domain.ErrWebAuthnSessionNotFound if it does not exist, has expired or belongs to another ceremony.
+	Consume(ctx context.Context, id string, ceremony domain.WebAuthnCeremony) (*domain.WebAuthnSession, error)
+
+	// DeleteExpired removes sessions that have expired
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// PhoneOTPRepository defines the interface for the SMS login codes, at most one active per user
+type PhoneOTPRepository interface {
+	// Save stores a new code for the user replacing any previous one
+	Save(ctx context.Context, otp *domain.PhoneOTP) error
+
+	// ReserveAttempt atomically counts a verification attempt against the active code of the user and returns it.
+	// It returns domain.ErrInvalidSMSCode if there is no active code or its maxAttempts are exhausted.
+	ReserveAttempt(ctx context.Context, userID string, maxAttempts int) (*domain.PhoneOTP, error)
+
+	// Consume deletes the code with the given hash so it can only be used once.
+	// It returns domain.ErrInvalidSMSCode if it was already consumed or replaced.
+	Consume(ctx context.Context, userID, codeHash string) error
+
+	// DeleteExpired removes codes that have expired
+	DeleteExpired(ctx context.Context) (int64, error)
 }
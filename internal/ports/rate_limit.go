@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+// RateLimiter define la interfaz de los almacenes de token buckets usados para limitar peticiones
+type RateLimiter interface {
+	// Allow consume un token del bucket identificado por key
+	Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitResult, error)
+	// DeleteExpired descarta los buckets ya recargados por completo
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -11,33 +11,188 @@ type VerifyUserRequest struct {
 	Password        string `json:"password" binding:"required"`
 }
 
-type UserInfoOAuth struct {
-	ID          string `json:"id"`
-	Email       string `json:"email"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	NickName    string `json:"nick_name"`
-	Role        string `json:"role"`
-	HasPassword bool   `json:"has_password"`
+type OAuthLoginRequest struct {
+	Provider string `json:"provider" binding:"required" example:"google"`
+	IDToken  string `json:"id_token" binding:"required"`
 }
 
 // AuthService define la interfaz para el servicio de autenticación
 type AuthService interface {
 	Login(ctx context.Context, req VerifyUserRequest) (*domain.LoginResponse, error)
-	OauthLogin(ctx context.Context, req UserInfoOAuth) (*domain.LoginResponse, error)
+	OauthLogin(ctx context.Context, req OAuthLoginRequest) (*domain.LoginResponse, error)
+	LoginMFA(ctx context.Context, req domain.MFALoginRequest) (*domain.LoginResponse, error)
+	BeginMFAPasskey(ctx context.Context, req domain.MFAPasskeyRequest) (*domain.WebAuthnOptionsResponse, error)
+	BeginPasskeyLogin(ctx context.Context) (*domain.WebAuthnOptionsResponse, error)
+	LoginPasskey(ctx context.Context, req domain.PasskeyAssertionRequest) (*domain.LoginResponse, error)
+	RequestMagicLink(ctx context.Context, req domain.MagicLinkRequest) (string, error)
+	LoginMagicLink(ctx context.Context, req domain.MagicLinkVerifyRequest, nonce string) (*domain.LoginResponse, error)
+	RequestSMSCode(ctx context.Context, req domain.SMSLoginRequest) error
+	LoginSMS(ctx context.Context, req domain.SMSLoginVerifyRequest) (*domain.LoginResponse, error)
+	Register(ctx context.Context, req domain.RegisterRequest) (*domain.RegisterResponse, error)
 	ValidateToken(ctx context.Context, token string) (*domain.ValidateResponse, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*domain.RefreshResponse, error)
+	Authenticate(ctx context.Context, accessToken string) (*domain.JWTClaims, error)
+	Logout(ctx context.Context, claims *domain.JWTClaims, refreshToken string) error
+	LogoutAll(ctx context.Context, claims *domain.JWTClaims) error
+	ChangePassword(ctx context.Context, claims *domain.JWTClaims, req domain.ChangePasswordRequest) (*domain.PasswordChangeResponse, error)
+	SetPassword(ctx context.Context, claims *domain.JWTClaims, req domain.SetPasswordRequest) (*domain.PasswordChangeResponse, error)
+	UserInfo(ctx context.Context, claims *domain.JWTClaims) (*domain.OIDCUserInfo, error)
+	Introspect(ctx context.Context, token, tokenTypeHint string) (*domain.IntrospectionResponse, error)
+}
+
+// IdentityService define la interfaz para las identidades de proveedores externos vinculadas a los usuarios
+type IdentityService interface {
+	// Authenticate verifica el ID token y retorna el usuario vinculado a la identidad
+	Authenticate(ctx context.Context, provider, idToken string) (*domain.User, error)
+	Link(ctx context.Context, userID, provider, idToken string) (*domain.UserIdentity, error)
+	Unlink(ctx context.Context, userID, provider string) error
+	List(ctx context.Context, userID string) ([]*domain.UserIdentity, error)
+}
+
+// EmailVerificationService define la interfaz para la verificación de email de los usuarios
+type EmailVerificationService interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+	Resend(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) error
+}
+
+// PasswordService define la interfaz para la recuperación y el cambio de contraseña
+type PasswordService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+}
+
+// MFAService define la interfaz para el segundo factor TOTP y los códigos de recuperación
+type MFAService interface {
+	Status(ctx context.Context, userID string) (*domain.MFAStatusResponse, error)
+	Enroll(ctx context.Context, userID string) (*domain.MFAEnrollmentResponse, error)
+	Confirm(ctx context.Context, userID, code string) (*domain.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) (*domain.MFARecoveryCodesResponse, error)
+	// Factors retorna los factores con los que el usuario puede completar un challenge; vacío si no tiene MFA
+	Factors(ctx context.Context, userID string) ([]string, error)
+	// Verify comprueba un código TOTP o consume un código de recuperación y retorna el factor usado
+	Verify(ctx context.Context, userID, code string) (string, error)
+}
+
+// PasskeyService define la interfaz para las ceremonias WebAuthn y la gestión de passkeys
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, userID string) (*domain.WebAuthnOptionsResponse, error)
+	FinishRegistration(ctx context.Context, userID string, req domain.PasskeyRegistrationRequest) (*domain.PasskeyInfo, error)
+	List(ctx context.Context, userID string) (*domain.PasskeysResponse, error)
+	Delete(ctx context.Context, userID, id string) error
+	// BeginLogin y FinishLogin implementan el login sin contraseña con passkeys detectables
+	BeginLogin(ctx context.Context) (*domain.WebAuthnOptionsResponse, error)
+	FinishLogin(ctx context.Context, req domain.PasskeyAssertionRequest) (*domain.PasskeyAssertion, error)
+	// BeginVerification y Verify comprueban una passkey del usuario como segundo factor
+	BeginVerification(ctx context.Context, userID string) (*domain.WebAuthnOptionsResponse, error)
+	Verify(ctx context.Context, userID string, req domain.PasskeyAssertionRequest) (*domain.PasskeyAssertion, error)
+}
+
+// SecurityEventPublisher define la interfaz para notificar eventos de seguridad a otros servicios.
+// Publish debe llamarse dentro de la transacción del cambio de estado (ver Transactor): si falla,
+// la operación se deshace, y si la operación se deshace, el evento tampoco se publica.
+type SecurityEventPublisher interface {
+	Publish(ctx context.Context, event domain.SecurityEvent) error
+}
+
+// WebhookService define la interfaz para las suscripciones de webhooks y la entrega de sus eventos
+type WebhookService interface {
+	SecurityEventPublisher
+	CreateSubscription(ctx context.Context, req domain.CreateWebhookRequest) (*domain.WebhookSubscriptionCreated, error)
+	ListSubscriptions(ctx context.Context) (*domain.WebhookSubscriptionsResponse, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, query domain.WebhookDeliveryQuery) (*domain.WebhookDeliveriesResponse, error)
+	// Replay vuelve a poner en cola una entrega, normalmente una agotada (dead)
+	Replay(ctx context.Context, deliveryID string) error
+	// DeliverDue intenta las entregas pendientes cuyo reintento ya venció y retorna cuántas procesó
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// AccountService define la interfaz para la administración del estado de las cuentas
+type AccountService interface {
+	// Deactivate desactiva la cuenta y cierra todas sus sesiones
+	Deactivate(ctx context.Context, userID, actorID string) error
+}
+
+// AuditService define la interfaz para consultar el registro de auditoría de autenticación
+type AuditService interface {
+	// Query retorna una página de eventos que cumplen los filtros, del más reciente al más antiguo
+	Query(ctx context.Context, query domain.AuthEventQuery) (*domain.AuthEventsResponse, error)
+}
+
+// SessionService define la interfaz para la gestión de las sesiones del usuario en sus dispositivos
+type SessionService interface {
+	// List retorna las sesiones activas del usuario; currentSessionID marca la de la petición
+	List(ctx context.Context, userID, currentSessionID string) (*domain.SessionsResponse, error)
+	// Revoke cierra una sesión del usuario y revoca sus tokens
+	Revoke(ctx context.Context, userID, id string) error
+}
+
+// MagicLinkService define la interfaz para el login sin contraseña con enlaces enviados por email
+type MagicLinkService interface {
+	// Request envía el enlace si la cuenta existe y retorna el nonce que lo liga al navegador que lo pidió
+	Request(ctx context.Context, email string) (string, error)
+	// Consume valida el enlace junto con el nonce del navegador y retorna el usuario
+	Consume(ctx context.Context, token, nonce string) (*domain.User, error)
+}
+
+// SMSLoginService define la interfaz para el login con códigos de un solo uso enviados por SMS
+type SMSLoginService interface {
+	// Request envía un código al teléfono si pertenece a una cuenta activa
+	Request(ctx context.Context, phoneNumber string) error
+	// Verify comprueba y consume el código del usuario; cada código admite un número limitado de intentos
+	Verify(ctx context.Context, user *domain.User, code string) error
+}
+
+// LockoutService define la interfaz para el bloqueo progresivo de cuentas tras logins fallidos
+type LockoutService interface {
+	Check(ctx context.Context, userID string) error
+	RecordFailure(ctx context.Context, userID string) error
+	RecordSuccess(ctx context.Context, userID string)
+	Unlock(ctx context.Context, userID, actorID string) error
+}
+
+// ResourceServerRegistry define la interfaz para autenticar a los resource servers registrados
+type ResourceServerRegistry interface {
+	Authenticate(clientID, clientSecret string) error
 }
 
 // JWTService define la interfaz para el servicio de JWT
 type JWTService interface {
-	GenerateTokenPair(user *domain.User) (*domain.TokenPair, error)
+	GenerateTokenPair(user *domain.User, authCtx domain.AuthContext) (*domain.TokenPair, error)
+	GenerateMFAChallenge(user *domain.User, authCtx domain.AuthContext) (*domain.MFAChallenge, error)
 	ValidateToken(tokenString string, tokenType domain.TokenType) (*domain.JWTClaims, error)
 	ParseToken(tokenString string) (*domain.JWTClaims, error)
+	JWKS() (*domain.JWKS, error)
+	Issuer() string
+}
+
+// Keyring define la interfaz del conjunto de claves de firma de tokens
+type Keyring interface {
+	// ActiveKey retorna la clave con la que se firman los nuevos tokens
+	ActiveKey() *domain.SigningKey
+	// VerificationKey retorna la clave con el kid indicado si todavía puede verificar tokens
+	VerificationKey(kid string) (*domain.SigningKey, error)
+	// Keys retorna las claves vigentes, empezando por la activa
+	Keys() []*domain.SigningKey
+	// Rotate genera una nueva clave activa y retira la anterior durante el periodo de gracia
+	Rotate(ctx context.Context) (*domain.SigningKey, error)
+	// Refresh recarga las claves compartidas por todas las réplicas
+	Refresh(ctx context.Context) error
 }
 
 // UserService define la interfaz para el cliente del servicio de usuarios
 type UserService interface {
+	GetUserByID(ctx context.Context, id string) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByEmailOrNickName(ctx context.Context, emailOrNickName string) (*domain.User, error)
+	GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User) error
+	UpdateUser(ctx context.Context, user *domain.User) error
+	SetPassword(ctx context.Context, user *domain.User, password string) error
+	RegisterUser(ctx context.Context, req domain.RegisterRequest) (*domain.User, error)
+	ExistsByNickName(ctx context.Context, nickName string) (bool, error)
 	VerifyUser(ctx context.Context, req VerifyUserRequest) (*domain.User, error)
+	UnlockUser(ctx context.Context, userID, actorID string) error
 }
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+// SMSSender define la interfaz para enviar SMS a los usuarios
+type SMSSender interface {
+	Send(ctx context.Context, message domain.SMSMessage) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/bikes2road/authentication/internal/domain"
+)
+
+// WebhookSender define la interfaz para enviar las peticiones firmadas de los webhooks
+type WebhookSender interface {
+	// Send envía la petición y retorna el código de estado de la respuesta.
+	// Las respuestas que no son 2xx se retornan como error junto con su código.
+	Send(ctx context.Context, req domain.WebhookRequest) (int, error)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/google/uuid"
+)
+
+type accountService struct {
+	userService      ports.UserService
+	refreshTokenRepo ports.RefreshTokenRepository
+	revocationRepo   ports.TokenRevocationRepository
+	sessionRepo      ports.SessionRepository
+	auditLogger      ports.AuditLogger
+	events           ports.SecurityEventPublisher
+	transactor       ports.Transactor
+}
+
+// NewAccountService crea una nueva instancia del servicio de administración de cuentas
+func NewAccountService(
+	userService ports.UserService,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	revocationRepo ports.TokenRevocationRepository,
+	sessionRepo ports.SessionRepository,
+	auditLogger ports.AuditLogger,
+	events ports.SecurityEventPublisher,
+	transactor ports.Transactor,
+) ports.AccountService {
+	return &accountService{
+		userService:      userService,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationRepo:   revocationRepo,
+		sessionRepo:      sessionRepo,
+		auditLogger:      auditLogger,
+		events:           events,
+		transactor:       transactor,
+	}
+}
+
+// Deactivate impide que la cuenta vuelva a iniciar sesión y revoca todos sus tokens.
+// Desactivar una cuenta ya inactiva no hace nada.
+func (s *accountService) Deactivate(ctx context.Context, userID, actorID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	user.IsActive = false
+	user.DateUpdated = time.Now()
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userService.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+
+		if err := revokeUserSessions(ctx, s.refreshTokenRepo, s.revocationRepo, s.sessionRepo, user.ID); err != nil {
+			return err
+		}
+
+		if err := s.events.Publish(ctx, domain.SecurityEvent{
+			Type:   domain.SecurityEventUserDeactivated,
+			UserID: user.ID,
+			Data:   map[string]string{"actor_id": actorID},
+		}); err != nil {
+			return err
+		}
+		return publishSessionsRevoked(ctx, s.events, user.ID, sessionsRevokedUserDeactivated, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       domain.AuthEventAccountDeactivated,
+		UserID:     user.ID,
+		Reason:     "deactivated by an administrator",
+		Metadata:   map[string]string{"actor_id": actorID},
+		OccurredAt: time.Now(),
+	})
+	return nil
+}
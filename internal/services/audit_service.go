@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+)
+
+type auditService struct {
+	repo ports.AuthEventRepository
+}
+
+// NewAuditService crea una nueva instancia del servicio de consulta de auditoría
+func NewAuditService(repo ports.AuthEventRepository) ports.AuditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+// Query retorna una página de eventos. El cursor es opaco y apunta al último evento de la página anterior,
+// por lo que los eventos escritos mientras se pagina no desplazan las páginas siguientes.
+func (s *auditService) Query(ctx context.Context, query domain.AuthEventQuery) (*domain.AuthEventsResponse, error) {
+	validation := domain.NewValidationError()
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultAuditPageSize
+	} else if limit < 0 || limit > maxAuditPageSize {
+		validation.Add("limit", "must be between 1 and "+strconv.Itoa(maxAuditPageSize))
+	}
+
+	var beforeID int64
+	if query.Cursor != "" {
+		id, ok := decodeAuditCursor(query.Cursor)
+		if !ok {
+			validation.Add("cursor", "is invalid")
+		}
+		beforeID = id
+	}
+
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		validation.Add("to", "must be after from")
+	}
+
+	if validation.HasErrors() {
+		return nil, validation
+	}
+
+	// Se pide un evento de más para saber si hay una página siguiente
+	events, err := s.repo.List(ctx, domain.AuthEventFilter{
+		Type:          query.Type,
+		UserID:        query.UserID,
+		Identifier:    query.Identifier,
+		IP:            query.IP,
+		Outcome:       query.Outcome,
+		CorrelationID: query.CorrelationID,
+		From:          query.From,
+		To:            query.To,
+		BeforeID:      beforeID,
+		Limit:         limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.AuthEventsResponse{Events: events}
+	if len(events) > limit {
+		response.Events = events[:limit]
+		response.NextCursor = encodeAuditCursor(events[limit-1].ID)
+	}
+	return response, nil
+}
+
+func encodeAuditCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeAuditCursor(cursor string) (int64, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(string(decoded), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
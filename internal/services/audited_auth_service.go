@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// auditedAuthService registra en el log de auditoría el resultado de cada operación de autenticación
+type auditedAuthService struct {
+	next        ports.AuthService
+	jwtService  ports.JWTService
+	auditLogger ports.AuditLogger
+}
+
+// NewAuditedAuthService envuelve el servicio de autenticación registrando un evento por operación.
+// Authenticate, que se ejecuta en cada petición protegida, solo registra los tokens rechazados.
+func NewAuditedAuthService(next ports.AuthService, jwtService ports.JWTService, auditLogger ports.AuditLogger) ports.AuthService {
+	return &auditedAuthService{
+		next:        next,
+		jwtService:  jwtService,
+		auditLogger: auditLogger,
+	}
+}
+
+func (s *auditedAuthService) Login(ctx context.Context, req ports.VerifyUserRequest) (*domain.LoginResponse, error) {
+	response, err := s.next.Login(ctx, req)
+	s.recordLogin(ctx, domain.AuthEventLogin, req.EmailOrNickName, "", nil, response, err)
+	return response, err
+}
+
+func (s *auditedAuthService) OauthLogin(ctx context.Context, req ports.OAuthLoginRequest) (*domain.LoginResponse, error) {
+	response, err := s.next.OauthLogin(ctx, req)
+	s.recordLogin(ctx, domain.AuthEventOAuthLogin, req.Provider, "", nil, response, err)
+	return response, err
+}
+
+func (s *auditedAuthService) LoginMFA(ctx context.Context, req domain.MFALoginRequest) (*domain.LoginResponse, error) {
+	response, err := s.next.LoginMFA(ctx, req)
+	factor := "code"
+	if len(req.Credential) > 0 {
+		factor = domain.FactorWebAuthn
+	}
+	s.recordLogin(ctx, domain.AuthEventMFALogin, "", s.subject(req.ChallengeToken), map[string]string{"factor": factor}, response, err)
+	return response, err
+}
+
+func (s *auditedAuthService) BeginMFAPasskey(ctx context.Context, req domain.MFAPasskeyRequest) (*domain.WebAuthnOptionsResponse, error) {
+	response, err := s.next.BeginMFAPasskey(ctx, req)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventMFAPasskeyBegin, UserID: s.subject(req.ChallengeToken)}, err)
+	return response, err
+}
+
+func (s *auditedAuthService) BeginPasskeyLogin(ctx context.Context) (*domain.WebAuthnOptionsResponse, error) {
+	response, err := s.next.BeginPasskeyLogin(ctx)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventPasskeyLoginBegin}, err)
+	return response, err
+}
+
+func (s *auditedAuthService) LoginPasskey(ctx context.Context, req domain.PasskeyAssertionRequest) (*domain.LoginResponse, error) {
+	response, err := s.next.LoginPasskey(ctx, req)
+	s.recordLogin(ctx, domain.AuthEventPasskeyLogin, "", "", nil, response, err)
+	return response, err
+}
+
+func (s *auditedAuthService) RequestMagicLink(ctx context.Context, req domain.MagicLinkRequest) (string, error) {
+	nonce, err := s.next.RequestMagicLink(ctx, req)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventMagicLinkRequest, Identifier: req.Email}, err)
+	return nonce, err
+}
+
+func (s *auditedAuthService) LoginMagicLink(ctx context.Context, req domain.MagicLinkVerifyRequest, nonce string) (*domain.LoginResponse, error) {
+	response, err := s.next.LoginMagicLink(ctx, req, nonce)
+	s.recordLogin(ctx, domain.AuthEventMagicLinkLogin, "", "", nil, response, err)
+	return response, err
+}
+
+func (s *auditedAuthService) RequestSMSCode(ctx context.Context, req domain.SMSLoginRequest) error {
+	err := s.next.RequestSMSCode(ctx, req)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventSMSCodeRequest, Identifier: req.PhoneNumber}, err)
+	return err
+}
+
+func (s *auditedAuthService) LoginSMS(ctx context.Context, req domain.SMSLoginVerifyRequest) (*domain.LoginResponse, error) {
+	response, err := s.next.LoginSMS(ctx, req)
+	s.recordLogin(ctx, domain.AuthEventSMSLogin, req.PhoneNumber, "", nil, response, err)
+	return response, err
+}
+
+func (s *auditedAuthService) Register(ctx context.Context, req domain.RegisterRequest) (*domain.RegisterResponse, error) {
+	response, err := s.next.Register(ctx, req)
+	event := domain.AuthEvent{Type: domain.AuthEventRegister, Identifier: req.Email}
+	if err == nil {
+		event.UserID = response.User.ID
+	}
+	s.record(ctx, event, err)
+	return response, err
+}
+
+func (s *auditedAuthService) ValidateToken(ctx context.Context, token string) (*domain.ValidateResponse, error) {
+	response, err := s.next.ValidateToken(ctx, token)
+	event := domain.AuthEvent{Type: domain.AuthEventTokenValidated, UserID: s.subject(token)}
+	if err == nil && !response.Valid {
+		event.Outcome = domain.AuthOutcomeFailure
+		event.Reason = "invalid token"
+	}
+	s.record(ctx, event, err)
+	return response, err
+}
+
+func (s *auditedAuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.RefreshResponse, error) {
+	response, err := s.next.RefreshToken(ctx, refreshToken)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventTokenRefreshed, UserID: s.subject(refreshToken)}, err)
+	return response, err
+}
+
+func (s *auditedAuthService) Authenticate(ctx context.Context, accessToken string) (*domain.JWTClaims, error) {
+	claims, err := s.next.Authenticate(ctx, accessToken)
+	if err != nil {
+		s.record(ctx, domain.AuthEvent{Type: domain.AuthEventTokenRejected, UserID: s.subject(accessToken)}, err)
+	}
+	return claims, err
+}
+
+func (s *auditedAuthService) Logout(ctx context.Context, claims *domain.JWTClaims, refreshToken string) error {
+	err := s.next.Logout(ctx, claims, refreshToken)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventLogout, UserID: claims.UserID, Metadata: sessionMetadata(claims)}, err)
+	return err
+}
+
+func (s *auditedAuthService) LogoutAll(ctx context.Context, claims *domain.JWTClaims) error {
+	err := s.next.LogoutAll(ctx, claims)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventLogoutAll, UserID: claims.UserID}, err)
+	return err
+}
+
+func (s *auditedAuthService) ChangePassword(ctx context.Context, claims *domain.JWTClaims, req domain.ChangePasswordRequest) (*domain.PasswordChangeResponse, error) {
+	response, err := s.next.ChangePassword(ctx, claims, req)
+	s.record(ctx, domain.AuthEvent{
+		Type:     domain.AuthEventPasswordChanged,
+		UserID:   claims.UserID,
+		Metadata: map[string]string{"logout_other_sessions": strconv.FormatBool(req.LogoutOtherSessions)},
+	}, err)
+	return response, err
+}
+
+func (s *auditedAuthService) SetPassword(ctx context.Context, claims *domain.JWTClaims, req domain.SetPasswordRequest) (*domain.PasswordChangeResponse, error) {
+	response, err := s.next.SetPassword(ctx, claims, req)
+	s.record(ctx, domain.AuthEvent{
+		Type:     domain.AuthEventPasswordSet,
+		UserID:   claims.UserID,
+		Metadata: map[string]string{"logout_other_sessions": strconv.FormatBool(req.LogoutOtherSessions)},
+	}, err)
+	return response, err
+}
+
+func (s *auditedAuthService) UserInfo(ctx context.Context, claims *domain.JWTClaims) (*domain.OIDCUserInfo, error) {
+	response, err := s.next.UserInfo(ctx, claims)
+	s.record(ctx, domain.AuthEvent{Type: domain.AuthEventUserInfo, UserID: claims.UserID}, err)
+	return response, err
+}
+
+func (s *auditedAuthService) Introspect(ctx context.Context, token, tokenTypeHint string) (*domain.IntrospectionResponse, error) {
+	response, err := s.next.Introspect(ctx, token, tokenTypeHint)
+	event := domain.AuthEvent{Type: domain.AuthEventTokenIntrospected, UserID: s.subject(token)}
+	if err == nil {
+		event.Metadata = map[string]string{"active": strconv.FormatBool(response.Active)}
+	}
+	s.record(ctx, event, err)
+	return response, err
+}
+
+// recordLogin registra un intento de login. El usuario se toma de la respuesta, del challenge MFA
+// emitido o, si se conoce de antemano, de userID.
+func (s *auditedAuthService) recordLogin(
+	ctx context.Context,
+	eventType domain.AuthEventType,
+	identifier, userID string,
+	metadata map[string]string,
+	response *domain.LoginResponse,
+	err error,
+) {
+	event := domain.AuthEvent{
+		Type:       eventType,
+		UserID:     userID,
+		Identifier: identifier,
+		Metadata:   metadata,
+	}
+
+	if err == nil {
+		switch {
+		case response.MFARequired:
+			event.Outcome = domain.AuthOutcomeMFARequired
+			event.UserID = s.subject(response.MFA.ChallengeToken)
+		case response.User != nil:
+			event.UserID = response.User.ID
+		}
+	}
+
+	s.record(ctx, event, err)
+}
+
+// record completa el resultado del evento a partir del error de la operación y lo registra
+func (s *auditedAuthService) record(ctx context.Context, event domain.AuthEvent, err error) {
+	if err != nil {
+		event.Outcome = domain.AuthOutcomeFailure
+		event.Reason = err.Error()
+	} else if event.Outcome == "" {
+		event.Outcome = domain.AuthOutcomeSuccess
+	}
+
+	s.auditLogger.Record(ctx, event)
+}
+
+// subject retorna el usuario de un token vigente con firma válida, sea del tipo que sea, para atribuir
+// también los intentos fallidos. Los tokens expirados o que no verifican no se atribuyen a nadie.
+func (s *auditedAuthService) subject(token string) string {
+	claims, err := s.jwtService.ParseToken(token)
+	if err != nil {
+		return ""
+	}
+	return claims.UserID
+}
+
+// sessionMetadata retorna la sesión de los claims para el evento, si la tienen
+func sessionMetadata(claims *domain.JWTClaims) map[string]string {
+	if claims.SessionID == "" {
+		return nil
+	}
+	return map[string]string{"session_id": claims.SessionID}
+}
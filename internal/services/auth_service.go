@@ -2,26 +2,86 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"time"
 
 	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/google/uuid"
 )
 
+// AuthServiceConfig contiene las opciones de comportamiento del servicio de autenticación
+type AuthServiceConfig struct {
+	// IssueTokensOnRegister inicia sesión tras el registro devolviendo un par de tokens
+	IssueTokensOnRegister bool
+	// RequireVerifiedEmail rechaza el login con contraseña de usuarios sin email verificado
+	RequireVerifiedEmail bool
+	// RecentAuthMaxAge es la antigüedad máxima de la autenticación para establecer la primera contraseña
+	RecentAuthMaxAge time.Duration
+}
+
 type authService struct {
-	jwtService  ports.JWTService
-	userService ports.UserService
+	jwtService       ports.JWTService
+	userService      ports.UserService
+	refreshTokenRepo ports.RefreshTokenRepository
+	revocationRepo   ports.TokenRevocationRepository
+	sessionRepo      ports.SessionRepository
+	auditLogger      ports.AuditLogger
+	events           ports.SecurityEventPublisher
+	transactor       ports.Transactor
+	identityService  ports.IdentityService
+	emailVerifier    ports.EmailVerificationService
+	mfaService       ports.MFAService
+	passkeyService   ports.PasskeyService
+	magicLinks       ports.MagicLinkService
+	smsLogin         ports.SMSLoginService
+	lockout          ports.LockoutService
+	config           AuthServiceConfig
 }
 
 // NewAuthService crea una nueva instancia del servicio de autenticación
-func NewAuthService(jwtService ports.JWTService, userService ports.UserService) ports.AuthService {
+func NewAuthService(
+	jwtService ports.JWTService,
+	userService ports.UserService,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	revocationRepo ports.TokenRevocationRepository,
+	sessionRepo ports.SessionRepository,
+	auditLogger ports.AuditLogger,
+	events ports.SecurityEventPublisher,
+	transactor ports.Transactor,
+	identityService ports.IdentityService,
+	emailVerifier ports.EmailVerificationService,
+	mfaService ports.MFAService,
+	passkeyService ports.PasskeyService,
+	magicLinks ports.MagicLinkService,
+	smsLogin ports.SMSLoginService,
+	lockout ports.LockoutService,
+	config AuthServiceConfig,
+) ports.AuthService {
 	return &authService{
-		jwtService:  jwtService,
-		userService: userService,
+		jwtService:       jwtService,
+		userService:      userService,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationRepo:   revocationRepo,
+		sessionRepo:      sessionRepo,
+		auditLogger:      auditLogger,
+		events:           events,
+		transactor:       transactor,
+		identityService:  identityService,
+		emailVerifier:    emailVerifier,
+		mfaService:       mfaService,
+		passkeyService:   passkeyService,
+		magicLinks:       magicLinks,
+		smsLogin:         smsLogin,
+		lockout:          lockout,
+		config:           config,
 	}
 }
 
-// Login autentica un usuario y genera tokens JWT
+// Login autentica un usuario y genera tokens JWT, o un challenge MFA si tiene segundo factor
 func (s *authService) Login(ctx context.Context, req ports.VerifyUserRequest) (*domain.LoginResponse, error) {
 	// Obtener usuario del servicio de usuarios
 	user, err := s.userService.VerifyUser(ctx, req)
@@ -29,58 +89,233 @@ func (s *authService) Login(ctx context.Context, req ports.VerifyUserRequest) (*
 		return nil, err
 	}
 
-	// Generar tokens
-	tokens, err := s.jwtService.GenerateTokenPair(user)
+	if s.config.RequireVerifiedEmail && !user.EmailVerified {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	return s.completeLogin(ctx, user, domain.AuthContext{
+		AuthTime: time.Now(),
+		Methods:  []string{domain.AMRPassword},
+	})
+}
+
+func (s *authService) OauthLogin(ctx context.Context, req ports.OAuthLoginRequest) (*domain.LoginResponse, error) {
+	// Verificar el ID token y resolver el usuario por la identidad vinculada; nada del body se usa como identidad
+	user, err := s.identityService.Authenticate(ctx, req.Provider, req.IDToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, err
 	}
 
-	// Construir respuesta
-	response := &domain.LoginResponse{
-		User: &domain.UserInfo{
-			ID:          user.ID,
-			Email:       user.Email,
-			NickName:    user.NickName,
-			FirstName:   user.FirstName,
-			LastName:    user.LastName,
-			Role:        user.Role,
-			HasPassword: user.HasPassword,
-		},
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	return s.completeLogin(ctx, user, domain.AuthContext{
+		AuthTime: time.Now(),
+		Methods:  []string{domain.AMRFederated},
+	})
+}
+
+// LoginMFA completa el login con el challenge emitido tras el primer factor y un código TOTP o de recuperación,
+// o una passkey. Los códigos incorrectos cuentan para el bloqueo de la cuenta igual que las contraseñas incorrectas.
+func (s *authService) LoginMFA(ctx context.Context, req domain.MFALoginRequest) (*domain.LoginResponse, error) {
+	claims, err := s.validateToken(ctx, req.ChallengeToken, domain.MFAChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lockout.Check(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	// Una passkey no puede ser a la vez primer y segundo factor
+	if len(req.Credential) > 0 && slices.Contains(claims.AMR, domain.AMRHardwareKey) {
+		return nil, domain.ErrMFAFactorNotAllowed
+	}
+
+	factor, err := s.verifySecondFactor(ctx, user.ID, req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) || errors.Is(err, domain.ErrInvalidPasskey) {
+			if err := s.recordFailure(ctx, user.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	s.lockout.RecordSuccess(ctx, user.ID)
+
+	// El challenge es de un solo uso: se consume con un único insert condicional, de modo que
+	// de dos peticiones concurrentes con el mismo challenge solo una obtiene tokens. Si la emisión
+	// falla la transacción se deshace y el challenge sigue valiendo.
+	authCtx := domain.AuthContextFromClaims(claims).WithMethods(factorMethod(factor), domain.AMRMFA)
+	var tokens *domain.TokenPair
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		consumed, err := s.revocationRepo.Consume(ctx, claims.ID, user.ID, time.Unix(claims.ExpiresAt, 0))
+		if err != nil {
+			return fmt.Errorf("failed to consume mfa challenge: %w", err)
+		}
+		if !consumed {
+			return domain.ErrTokenRevoked
+		}
+
+		tokens, err = s.issueTokens(ctx, user, authCtx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		User:   newUserInfo(user),
 		Tokens: tokens,
+	}, nil
+}
+
+// BeginMFAPasskey inicia la verificación con passkey de un challenge MFA pendiente
+func (s *authService) BeginMFAPasskey(ctx context.Context, req domain.MFAPasskeyRequest) (*domain.WebAuthnOptionsResponse, error) {
+	claims, err := s.validateToken(ctx, req.ChallengeToken, domain.MFAChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(claims.AMR, domain.AMRHardwareKey) {
+		return nil, domain.ErrMFAFactorNotAllowed
 	}
 
-	return response, nil
+	return s.passkeyService.BeginVerification(ctx, claims.UserID)
+}
+
+// BeginPasskeyLogin inicia un login sin contraseña con passkey
+func (s *authService) BeginPasskeyLogin(ctx context.Context) (*domain.WebAuthnOptionsResponse, error) {
+	return s.passkeyService.BeginLogin(ctx)
 }
 
-func (s *authService) OauthLogin(ctx context.Context, req ports.UserInfoOAuth) (*domain.LoginResponse, error) {
-	user := &domain.User{
-		ID:          req.ID,
-		Email:       req.Email,
-		NickName:    req.NickName,
-		FirstName:   req.FirstName,
-		LastName:    req.LastName,
-		Role:        req.Role,
-		HasPassword: req.HasPassword,
+// LoginPasskey completa un login sin contraseña. Si el autenticador verificó al usuario (PIN o biometría)
+// la passkey cuenta como autenticación multifactor; si solo comprobó su presencia se aplica el challenge MFA
+// con los factores que no son passkeys.
+func (s *authService) LoginPasskey(ctx context.Context, req domain.PasskeyAssertionRequest) (*domain.LoginResponse, error) {
+	assertion, err := s.passkeyService.FinishLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.activeUser(ctx, assertion.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	authCtx := domain.AuthContext{
+		AuthTime: time.Now(),
+		Methods:  []string{domain.AMRHardwareKey},
+	}
+	if !assertion.UserVerified {
+		return s.completeLogin(ctx, user, authCtx)
 	}
 
-	// Generar tokens
-	tokens, err := s.jwtService.GenerateTokenPair(user)
+	tokens, err := s.issueTokens(ctx, user, authCtx.WithMethods(domain.AMRMFA))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, err
 	}
 
-	// Construir respuesta
-	response := &domain.LoginResponse{
-		User: &domain.UserInfo{
-			ID:          user.ID,
-			Email:       user.Email,
-			NickName:    user.NickName,
-			FirstName:   user.FirstName,
-			LastName:    user.LastName,
-			Role:        user.Role,
-			HasPassword: user.HasPassword,
-		},
+	return &domain.LoginResponse{
+		User:   newUserInfo(user),
 		Tokens: tokens,
+	}, nil
+}
+
+// RequestMagicLink envía un enlace de login por email y retorna el nonce que el navegador debe presentar al usarlo
+func (s *authService) RequestMagicLink(ctx context.Context, req domain.MagicLinkRequest) (string, error) {
+	return s.magicLinks.Request(ctx, req.Email)
+}
+
+// LoginMagicLink consume el enlace de login y genera tokens JWT, o un challenge MFA si el usuario tiene segundo factor
+func (s *authService) LoginMagicLink(ctx context.Context, req domain.MagicLinkVerifyRequest, nonce string) (*domain.LoginResponse, error) {
+	user, err := s.magicLinks.Consume(ctx, req.Token, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, domain.AuthContext{
+		AuthTime: time.Now(),
+		Methods:  []string{domain.AMREmail},
+	})
+}
+
+// RequestSMSCode envía un código de login por SMS al teléfono indicado
+func (s *authService) RequestSMSCode(ctx context.Context, req domain.SMSLoginRequest) error {
+	return s.smsLogin.Request(ctx, req.PhoneNumber)
+}
+
+// LoginSMS verifica el código recibido por SMS y genera tokens JWT, o un challenge MFA si el usuario tiene segundo factor
+func (s *authService) LoginSMS(ctx context.Context, req domain.SMSLoginVerifyRequest) (*domain.LoginResponse, error) {
+	// Un teléfono mal formado o sin cuenta responde igual que un código incorrecto para no revelar qué números están registrados
+	phoneNumber, err := domain.NormalizePhoneNumber(req.PhoneNumber)
+	if err != nil {
+		return nil, domain.ErrInvalidSMSCode
+	}
+
+	user, err := s.userService.GetUserByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidSMSCode
+		}
+		return nil, err
+	}
+
+	if err := s.lockout.Check(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.smsLogin.Verify(ctx, user, req.Code); err != nil {
+		if errors.Is(err, domain.ErrInvalidSMSCode) {
+			if err := s.recordFailure(ctx, user.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	s.lockout.RecordSuccess(ctx, user.ID)
+
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	return s.completeLogin(ctx, user, domain.AuthContext{
+		AuthTime: time.Now(),
+		Methods:  []string{domain.AMRSMS},
+	})
+}
+
+// Register crea un usuario con contraseña y, si está configurado, le emite tokens
+func (s *authService) Register(ctx context.Context, req domain.RegisterRequest) (*domain.RegisterResponse, error) {
+	user, err := s.userService.RegisterUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// El registro no falla si el email no se puede enviar: el usuario puede pedir el reenvío
+	if err := s.emailVerifier.SendVerification(ctx, user); err != nil {
+		log.Printf("failed to send verification email to user %s: %v", user.ID, err)
+	}
+
+	response := &domain.RegisterResponse{
+		User: newUserInfo(user),
+	}
+
+	// Si se exige email verificado, el usuario recién registrado todavía no puede iniciar sesión
+	if s.config.IssueTokensOnRegister && !s.config.RequireVerifiedEmail {
+		tokens, err := s.issueTokens(ctx, user, domain.AuthContext{
+			AuthTime: time.Now(),
+			Methods:  []string{domain.AMRPassword},
+		})
+		if err != nil {
+			return nil, err
+		}
+		response.Tokens = tokens
 	}
 
 	return response, nil
@@ -88,7 +323,7 @@ func (s *authService) OauthLogin(ctx context.Context, req ports.UserInfoOAuth) (
 
 // ValidateToken valida un token JWT
 func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.ValidateResponse, error) {
-	claims, err := s.jwtService.ValidateToken(token, domain.AccessToken)
+	claims, err := s.Authenticate(ctx, token)
 	if err != nil {
 		return &domain.ValidateResponse{
 			Valid:  false,
@@ -102,18 +337,36 @@ func (s *authService) ValidateToken(ctx context.Context, token string) (*domain.
 	}, nil
 }
 
-// RefreshToken refresca un token JWT usando el refresh token
+// RefreshToken refresca un token JWT usando el refresh token.
+// Cada uso rota el refresh token; presentar uno ya rotado revoca toda su familia.
+// Los tokens de una sesión cerrada no se pueden refrescar.
 func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*domain.RefreshResponse, error) {
 	// Validar el refresh token
-	claims, err := s.jwtService.ValidateToken(refreshToken, domain.RefreshToken)
+	claims, err := s.validateToken(ctx, refreshToken, domain.RefreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// Obtener usuario actualizado del servicio de usuarios
-	user, err := s.userService.GetUserByEmailOrNickName(ctx, claims.NickName)
+	// Verificar el estado del token en el servidor
+	current, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if current.IsRevoked() {
+		return nil, domain.ErrInvalidToken
+	}
+	if current.IsRotated() {
+		return nil, s.handleRefreshTokenReuse(ctx, current)
+	}
+
+	// Obtener usuario actualizado del servicio de usuarios por su ID: el nick del
+	// token puede haber cambiado o coincidir con el email de otra cuenta
+	user, err := s.userService.GetUserByID(ctx, claims.UserID)
 	if err != nil {
-		if err == domain.ErrUserNotFound {
+		if errors.Is(err, domain.ErrUserNotFound) {
 			return nil, domain.ErrInvalidToken
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -124,13 +377,565 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, domain.ErrUserInactive
 	}
 
-	// Generar nuevos tokens
-	tokens, err := s.jwtService.GenerateTokenPair(user)
+	// Los refresh tokens emitidos antes de existir las sesiones no llevan sid:
+	// su familia se adopta como sesión al rotarlos
+	authCtx := domain.AuthContextFromClaims(claims)
+	legacySession := authCtx.SessionID == ""
+	if legacySession {
+		authCtx.SessionID = current.FamilyID
+	}
+
+	// Generar nuevos tokens conservando el momento de la autenticación original
+	tokens, err := s.jwtService.GenerateTokenPair(user, authCtx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
+	next, err := s.newRefreshTokenRecord(user, current.FamilyID, tokens.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// La rotación y la sesión se guardan juntas: si falla la sesión el token actual sigue
+	// sin rotar y el cliente puede reintentar sin que parezca una reutilización
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Otra petición concurrente pudo haber rotado el mismo token
+		if err := s.refreshTokenRepo.Rotate(ctx, current.ID, next); err != nil {
+			if errors.Is(err, domain.ErrRefreshTokenReused) {
+				return err
+			}
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+
+		if legacySession {
+			return s.startSession(ctx, user, authCtx, next.ExpiresAt)
+		}
+		if err := s.sessionRepo.Touch(ctx, authCtx.SessionID, time.Now(), next.ExpiresAt); err != nil {
+			return fmt.Errorf("failed to update session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		// La familia se revoca fuera de la transacción deshecha
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			return nil, s.handleRefreshTokenReuse(ctx, current)
+		}
+		return nil, err
+	}
+
 	return &domain.RefreshResponse{
 		Tokens: tokens,
 	}, nil
 }
+
+// Authenticate valida un access token, incluida su revocación, y retorna sus claims
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*domain.JWTClaims, error) {
+	return s.validateToken(ctx, accessToken, domain.AccessToken)
+}
+
+// Logout revoca el access token actual y cierra su sesión junto con sus refresh tokens.
+// El refresh token enviado identifica la sesión de los tokens emitidos antes de existir el claim sid.
+func (s *authService) Logout(ctx context.Context, claims *domain.JWTClaims, refreshToken string) error {
+	if err := s.revocationRepo.Revoke(ctx, claims.ID, claims.UserID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	if claims.SessionID != "" {
+		if err := s.revokeSession(ctx, claims.UserID, claims.SessionID); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	refreshClaims, err := s.jwtService.ValidateToken(refreshToken, domain.RefreshToken)
+	if err != nil {
+		// Un refresh token expirado ya no puede usarse, no hay nada que revocar
+		if errors.Is(err, domain.ErrTokenExpired) {
+			return nil
+		}
+		return err
+	}
+	if refreshClaims.UserID != claims.UserID {
+		return domain.ErrInvalidToken
+	}
+
+	record, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return domain.ErrInvalidToken
+		}
+		return fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, record.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll revoca todas las sesiones del usuario: sus refresh tokens y todo token emitido hasta ahora
+func (s *authService) LogoutAll(ctx context.Context, claims *domain.JWTClaims) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := revokeUserSessions(ctx, s.refreshTokenRepo, s.revocationRepo, s.sessionRepo, claims.UserID); err != nil {
+			return err
+		}
+
+		return publishSessionsRevoked(ctx, s.events, claims.UserID, sessionsRevokedLogoutAll, "")
+	})
+}
+
+// ChangePassword cambia la contraseña del usuario autenticado tras comprobar la actual
+func (s *authService) ChangePassword(ctx context.Context, claims *domain.JWTClaims, req domain.ChangePasswordRequest) (*domain.PasswordChangeResponse, error) {
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.HasPassword {
+		return nil, domain.ErrPasswordNotSet
+	}
+
+	if _, err := s.userService.VerifyUser(ctx, ports.VerifyUserRequest{
+		EmailOrNickName: user.Email,
+		Password:        req.CurrentPassword,
+	}); err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			validation := domain.NewValidationError()
+			validation.Add("current_password", "is incorrect")
+			return nil, validation
+		}
+		return nil, err
+	}
+
+	// Introducir la contraseña actual cuenta como una autenticación nueva
+	return s.updatePassword(ctx, user, req.NewPassword, "change", req.LogoutOtherSessions, domain.AuthContext{AuthTime: time.Now()})
+}
+
+// SetPassword establece la primera contraseña de un usuario que solo ha usado OAuth.
+// Como no hay contraseña que comprobar, exige que la autenticación del token sea reciente.
+func (s *authService) SetPassword(ctx context.Context, claims *domain.JWTClaims, req domain.SetPasswordRequest) (*domain.PasswordChangeResponse, error) {
+	authCtx := domain.AuthContextFromClaims(claims)
+	if time.Since(authCtx.AuthTime) > s.config.RecentAuthMaxAge {
+		return nil, domain.ErrReauthenticationRequired
+	}
+
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.HasPassword {
+		return nil, domain.ErrPasswordAlreadySet
+	}
+
+	return s.updatePassword(ctx, user, req.Password, "set", req.LogoutOtherSessions, authCtx)
+}
+
+// UserInfo retorna los claims estándar de OpenID Connect del usuario del access token
+func (s *authService) UserInfo(ctx context.Context, claims *domain.JWTClaims) (*domain.OIDCUserInfo, error) {
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OIDCUserInfo{
+		Subject:           user.ID,
+		Email:             user.Email,
+		EmailVerified:     user.EmailVerified,
+		GivenName:         user.FirstName,
+		FamilyName:        user.LastName,
+		PreferredUsername: user.NickName,
+		Role:              user.Role,
+		HasPassword:       user.HasPassword,
+	}, nil
+}
+
+// Introspect informa el estado de un access o refresh token según RFC 7662.
+// Cualquier token inválido, expirado, revocado o ya rotado se reporta como inactivo.
+func (s *authService) Introspect(ctx context.Context, token, tokenTypeHint string) (*domain.IntrospectionResponse, error) {
+	tokenTypes := []domain.TokenType{domain.AccessToken, domain.RefreshToken}
+	if tokenTypeHint == "refresh_token" {
+		tokenTypes = []domain.TokenType{domain.RefreshToken, domain.AccessToken}
+	}
+
+	for _, tokenType := range tokenTypes {
+		claims, err := s.validateToken(ctx, token, tokenType)
+		if err != nil {
+			if isTokenValidationError(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if tokenType == domain.RefreshToken {
+			record, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(token))
+			if err != nil && !errors.Is(err, domain.ErrRefreshTokenNotFound) {
+				return nil, fmt.Errorf("failed to get refresh token: %w", err)
+			}
+			// Un refresh token ya rotado o revocado no puede ser otro tipo de token
+			if record == nil || record.IsRotated() || record.IsRevoked() {
+				break
+			}
+		}
+
+		return &domain.IntrospectionResponse{
+			Active:    true,
+			Scope:     claims.Scope,
+			ClientID:  claims.ClientID,
+			Username:  claims.NickName,
+			TokenType: introspectionTokenType(claims.TokenType),
+			ExpiresAt: claims.ExpiresAt,
+			IssuedAt:  claims.IssuedAt,
+			NotBefore: claims.NotBefore,
+			Subject:   claims.UserID,
+			Audience:  claims.Audience,
+			Issuer:    claims.Issuer,
+			ID:        claims.ID,
+		}, nil
+	}
+
+	return &domain.IntrospectionResponse{Active: false}, nil
+}
+
+// validateToken valida firma, expiración y tipo del token y verifica que no haya sido revocado
+// ni pertenezca a una sesión cerrada
+func (s *authService) validateToken(ctx context.Context, token string, tokenType domain.TokenType) (*domain.JWTClaims, error) {
+	claims, err := s.jwtService.ValidateToken(token, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.revocationRepo.IsRevoked(ctx, claims.ID, claims.UserID, time.Unix(claims.IssuedAt, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revoked {
+		return nil, domain.ErrTokenRevoked
+	}
+
+	if claims.SessionID != "" {
+		session, err := s.sessionRepo.Get(ctx, claims.SessionID)
+		if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, fmt.Errorf("failed to get session: %w", err)
+		}
+		if session == nil || session.IsRevoked() || session.UserID != claims.UserID {
+			return nil, domain.ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+// activeUser carga el usuario de un token y verifica que siga activo
+func (s *authService) activeUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	return user, nil
+}
+
+// updatePassword guarda la nueva contraseña, notifica el cambio y cierra opcionalmente el resto de sesiones,
+// emitiendo tokens nuevos para que la sesión actual continúe. Todo ocurre en una transacción.
+func (s *authService) updatePassword(ctx context.Context, user *domain.User, password, method string, logoutOtherSessions bool, authCtx domain.AuthContext) (*domain.PasswordChangeResponse, error) {
+	response := &domain.PasswordChangeResponse{
+		Message: "Password has been updated",
+	}
+
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userService.SetPassword(ctx, user, password); err != nil {
+			return err
+		}
+
+		if err := s.events.Publish(ctx, domain.SecurityEvent{
+			Type:   domain.SecurityEventPasswordChanged,
+			UserID: user.ID,
+			Data:   map[string]string{"method": method},
+		}); err != nil {
+			return err
+		}
+
+		if !logoutOtherSessions {
+			return nil
+		}
+
+		if err := revokeUserSessions(ctx, s.refreshTokenRepo, s.revocationRepo, s.sessionRepo, user.ID); err != nil {
+			return err
+		}
+		if err := publishSessionsRevoked(ctx, s.events, user.ID, sessionsRevokedPasswordChanged, ""); err != nil {
+			return err
+		}
+
+		tokens, err := s.issueTokens(ctx, user, authCtx)
+		if err != nil {
+			return err
+		}
+		response.Tokens = tokens
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// verifySecondFactor comprueba la passkey o el código del challenge MFA y retorna el factor usado
+func (s *authService) verifySecondFactor(ctx context.Context, userID string, req domain.MFALoginRequest) (string, error) {
+	if len(req.Credential) > 0 {
+		_, err := s.passkeyService.Verify(ctx, userID, domain.PasskeyAssertionRequest{
+			SessionID:  req.SessionID,
+			Credential: req.Credential,
+		})
+		if err != nil {
+			return "", err
+		}
+		return domain.FactorWebAuthn, nil
+	}
+
+	return s.mfaService.Verify(ctx, userID, req.Code)
+}
+
+// recordFailure cuenta un segundo factor o código incorrecto para el bloqueo de la cuenta.
+// Solo retorna error si la cuenta acaba de bloquearse; el resto de fallos se registran en el log.
+func (s *authService) recordFailure(ctx context.Context, userID string) error {
+	if err := s.lockout.RecordFailure(ctx, userID); err != nil {
+		var lockedErr *domain.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			return err
+		}
+		log.Printf("failed to record login failure for user %s: %v", userID, err)
+	}
+	return nil
+}
+
+// completeLogin emite los tokens tras el primer factor o, si el usuario tiene un segundo factor distinto
+// del usado en el primero, un challenge que debe completarse en LoginMFA
+func (s *authService) completeLogin(ctx context.Context, user *domain.User, authCtx domain.AuthContext) (*domain.LoginResponse, error) {
+	factors, err := s.mfaService.Factors(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa factors: %w", err)
+	}
+	// Solo se ofrecen factores distintos del primero: tras un login con passkey no se acepta otra passkey
+	factors = slices.DeleteFunc(factors, func(factor string) bool {
+		return slices.Contains(authCtx.Methods, factorMethod(factor))
+	})
+
+	if len(factors) > 0 {
+		challenge, err := s.jwtService.GenerateMFAChallenge(user, authCtx)
+		if err != nil {
+			return nil, err
+		}
+		challenge.Factors = factors
+
+		return &domain.LoginResponse{
+			MFARequired: true,
+			MFA:         challenge,
+		}, nil
+	}
+
+	tokens, err := s.issueTokens(ctx, user, authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginResponse{
+		User:   newUserInfo(user),
+		Tokens: tokens,
+	}, nil
+}
+
+// issueTokens inicia una nueva sesión y genera sus tokens. El ID de la sesión es el claim sid
+// y también la familia de sus refresh tokens.
+func (s *authService) issueTokens(ctx context.Context, user *domain.User, authCtx domain.AuthContext) (*domain.TokenPair, error) {
+	authCtx.SessionID = uuid.NewString()
+
+	tokens, err := s.jwtService.GenerateTokenPair(user, authCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	record, err := s.newRefreshTokenRecord(user, authCtx.SessionID, tokens.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// El dispositivo se comprueba antes de guardar la sesión, que pasaría a contar como conocida
+	knownDevice := s.isKnownDevice(ctx, user.ID)
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.startSession(ctx, user, authCtx, record.ExpiresAt); err != nil {
+			return err
+		}
+
+		if err := s.refreshTokenRepo.Create(ctx, record); err != nil {
+			return fmt.Errorf("failed to store refresh token: %w", err)
+		}
+
+		if knownDevice {
+			return nil
+		}
+		client := domain.ClientInfoFromContext(ctx)
+		return s.events.Publish(ctx, domain.SecurityEvent{
+			Type:   domain.SecurityEventNewDeviceLogin,
+			UserID: user.ID,
+			Data: map[string]string{
+				"session_id": authCtx.SessionID,
+				"ip":         client.IP,
+				"user_agent": client.UserAgent,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// isKnownDevice indica si el usuario ya tuvo una sesión desde el user agent de la petición.
+// Si no se puede comprobar se asume conocido para no alertar de un login sin motivo.
+func (s *authService) isKnownDevice(ctx context.Context, userID string) bool {
+	known, err := s.sessionRepo.IsKnownDevice(ctx, userID, domain.ClientInfoFromContext(ctx).UserAgent)
+	if err != nil {
+		log.Printf("failed to check known device for user %s: %v", userID, err)
+		return true
+	}
+	return known
+}
+
+// startSession persiste la sesión del contexto de autenticación con los datos del dispositivo de la petición
+func (s *authService) startSession(ctx context.Context, user *domain.User, authCtx domain.AuthContext, expiresAt time.Time) error {
+	client := domain.ClientInfoFromContext(ctx)
+	now := time.Now()
+
+	if err := s.sessionRepo.Create(ctx, &domain.Session{
+		ID:         authCtx.SessionID,
+		UserID:     user.ID,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		Methods:    authCtx.Methods,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// revokeSession cierra una sesión y revoca sus refresh tokens; una sesión ya cerrada no es un error
+func (s *authService) revokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.sessionRepo.Revoke(ctx, userID, sessionID); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+// newRefreshTokenRecord construye el registro persistible de un refresh token recién emitido
+func (s *authService) newRefreshTokenRecord(user *domain.User, familyID, refreshToken string) (*domain.RefreshTokenRecord, error) {
+	claims, err := s.jwtService.ParseToken(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issued refresh token: %w", err)
+	}
+
+	return &domain.RefreshTokenRecord{
+		ID:        uuid.NewString(),
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// handleRefreshTokenReuse cierra la sesión de un refresh token reutilizado, revocando su familia, y registra el evento
+func (s *authService) handleRefreshTokenReuse(ctx context.Context, token *domain.RefreshTokenRecord) error {
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.revokeSession(ctx, token.UserID, token.FamilyID); err != nil {
+			return err
+		}
+		return publishSessionsRevoked(ctx, s.events, token.UserID, sessionsRevokedRefreshTokenReuse, token.FamilyID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:   domain.AuthEventRefreshTokenReuse,
+		UserID: token.UserID,
+		Reason: "rotated refresh token presented again, session and token family revoked",
+		Metadata: map[string]string{
+			"family_id": token.FamilyID,
+			"token_id":  token.ID,
+		},
+		OccurredAt: time.Now(),
+	})
+
+	return domain.ErrRefreshTokenReused
+}
+
+// isTokenValidationError indica si el error se debe al token y no a un fallo interno
+func isTokenValidationError(err error) bool {
+	for _, tokenErr := range []error{
+		domain.ErrInvalidToken,
+		domain.ErrTokenExpired,
+		domain.ErrTokenMalformed,
+		domain.ErrTokenRevoked,
+		domain.ErrInvalidTokenType,
+		domain.ErrInvalidTokenAudience,
+	} {
+		if errors.Is(err, tokenErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// introspectionTokenType traduce el tipo interno del token al valor reportado en la introspección
+func introspectionTokenType(tokenType domain.TokenType) string {
+	if tokenType == domain.RefreshToken {
+		return "Refresh"
+	}
+	return "Bearer"
+}
+
+// newUserInfo construye la información del usuario que se devuelve en las respuestas de login
+func newUserInfo(user *domain.User) *domain.UserInfo {
+	return &domain.UserInfo{
+		ID:          user.ID,
+		Email:       user.Email,
+		NickName:    user.NickName,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Role:        user.Role,
+		HasPassword: user.HasPassword,
+	}
+}
+
+// factorMethod traduce el factor MFA usado a su valor amr
+func factorMethod(factor string) string {
+	switch factor {
+	case domain.FactorTOTP, domain.FactorRecoveryCode:
+		return domain.AMROTP
+	case domain.FactorWebAuthn:
+		return domain.AMRHardwareKey
+	default:
+		return factor
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// EmailVerificationConfig contiene la configuración de la verificación de email
+type EmailVerificationConfig struct {
+	// VerificationURL es la URL a la que apunta el enlace del email; se le añade ?token=...
+	VerificationURL string
+	// TokenTTL es la validez del enlace de verificación
+	TokenTTL time.Duration
+}
+
+type emailVerificationService struct {
+	userService ports.UserService
+	tokenRepo   ports.OneTimeTokenRepository
+	mailer      ports.Mailer
+	config      EmailVerificationConfig
+}
+
+// NewEmailVerificationService crea una nueva instancia del servicio de verificación de email
+func NewEmailVerificationService(
+	userService ports.UserService,
+	tokenRepo ports.OneTimeTokenRepository,
+	mailer ports.Mailer,
+	config EmailVerificationConfig,
+) ports.EmailVerificationService {
+	return &emailVerificationService{
+		userService: userService,
+		tokenRepo:   tokenRepo,
+		mailer:      mailer,
+		config:      config,
+	}
+}
+
+// SendVerification emite un nuevo enlace de verificación, invalidando los anteriores, y lo envía por email
+func (s *emailVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, domain.PurposeEmailVerification); err != nil {
+		return err
+	}
+
+	token, record, err := newOneTimeToken(domain.PurposeEmailVerification, user.ID, s.config.TokenTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	link := s.config.VerificationURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Verifica tu email en Bikes2Road",
+		Body: fmt.Sprintf("Hola %s,\n\nConfirma tu dirección de email abriendo este enlace:\n\n%s\n\nEl enlace caduca en %s. Si no has creado una cuenta en Bikes2Road, ignora este mensaje.\n",
+			user.FirstName, link, s.config.TokenTTL),
+	})
+}
+
+// Resend reenvía el enlace de verificación. No informa si el email existe o ya está verificado
+// para no permitir enumerar cuentas.
+func (s *emailVerificationService) Resend(ctx context.Context, email string) error {
+	// La búsqueda no distingue mayúsculas, así que también encuentra las cuentas guardadas con ellas
+	user, err := s.userService.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerified || !user.IsActive {
+		return nil
+	}
+
+	return s.SendVerification(ctx, user)
+}
+
+// Verify consume el token de verificación y marca el email del usuario como verificado
+func (s *emailVerificationService) Verify(ctx context.Context, token string) error {
+	record, err := s.tokenRepo.Consume(ctx, domain.PurposeEmailVerification, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userService.GetUserByID(ctx, record.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	user.EmailVerified = true
+	return s.userService.UpdateUser(ctx, user)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/google/uuid"
+)
+
+// maxNickNameAttempts limita los sufijos numéricos probados antes de recurrir a uno aleatorio
+const maxNickNameAttempts = 20
+
+// ProvisioningConfig contiene la configuración del alta automática de usuarios en el primer login OAuth
+type ProvisioningConfig struct {
+	// DefaultRole es el rol asignado a los usuarios creados automáticamente
+	DefaultRole string
+	// Providers indica, por proveedor, si se crean usuarios y para qué dominios de email
+	Providers map[string]ProviderProvisioning
+}
+
+// ProviderProvisioning contiene la política de alta automática de un proveedor
+type ProviderProvisioning struct {
+	Enabled bool
+	// AllowedEmailDomains restringe el alta a esos dominios; vacío permite cualquiera
+	AllowedEmailDomains []string
+}
+
+type identityService struct {
+	verifier     ports.IdentityVerifier
+	identityRepo ports.UserIdentityRepository
+	userService  ports.UserService
+	auditLogger  ports.AuditLogger
+	provisioning ProvisioningConfig
+}
+
+// NewIdentityService crea una nueva instancia del servicio de identidades externas
+func NewIdentityService(
+	verifier ports.IdentityVerifier,
+	identityRepo ports.UserIdentityRepository,
+	userService ports.UserService,
+	auditLogger ports.AuditLogger,
+	provisioning ProvisioningConfig,
+) ports.IdentityService {
+	if provisioning.DefaultRole == "" {
+		provisioning.DefaultRole = domain.RoleUser
+	}
+
+	return &identityService{
+		verifier:     verifier,
+		identityRepo: identityRepo,
+		userService:  userService,
+		auditLogger:  auditLogger,
+		provisioning: provisioning,
+	}
+}
+
+// Authenticate verifica el ID token y retorna el usuario vinculado a la identidad.
+// Si la identidad aún no está vinculada, se vincula al usuario con el mismo email solo si ambos lados lo
+// han verificado: una cuenta con el email sin verificar puede haberla creado otra persona, que seguiría
+// entrando con su contraseña. En ese caso el dueño debe iniciar sesión y vincular la identidad con Link.
+func (s *identityService) Authenticate(ctx context.Context, provider, idToken string) (*domain.User, error) {
+	identity, err := s.verifier.Verify(ctx, provider, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := s.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.userService.GetUserByID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, domain.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	// Primer login con esta identidad: solo un email verificado por el proveedor permite asociarla a una cuenta
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, domain.ErrIdentityEmailNotVerified
+	}
+
+	user, err := s.userService.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		user, err = s.provisionUser(ctx, identity)
+	case err == nil && !user.EmailVerified:
+		return nil, domain.ErrIdentityLinkRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.link(ctx, user.ID, identity); err != nil {
+		return nil, err
+	}
+	s.recordLink(ctx, user.ID, identity, "first login with the identity")
+
+	return user, nil
+}
+
+// Link vincula la identidad del ID token a la cuenta del usuario
+func (s *identityService) Link(ctx context.Context, userID, provider, idToken string) (*domain.UserIdentity, error) {
+	identity, err := s.verifier.Verify(ctx, provider, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := &domain.UserIdentity{
+		Provider:        identity.Provider,
+		ProviderSubject: identity.Subject,
+		UserID:          userID,
+		EmailAtLink:     identity.Email,
+		LinkedAt:        time.Now(),
+	}
+	if err := s.identityRepo.Create(ctx, linked); err != nil {
+		return nil, err
+	}
+	s.recordLink(ctx, userID, identity, "linked by the account owner")
+
+	return linked, nil
+}
+
+// Unlink desvincula el proveedor de la cuenta del usuario.
+// Se rechaza si la cuenta quedaría sin contraseña y sin ninguna identidad con la que iniciar sesión.
+func (s *identityService) Unlink(ctx context.Context, userID, provider string) error {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	identities, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	linked := false
+	for _, identity := range identities {
+		if identity.Provider == provider {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return domain.ErrIdentityNotFound
+	}
+
+	if !user.HasPassword && len(identities) <= 1 {
+		return domain.ErrLastLoginMethod
+	}
+
+	return s.identityRepo.Delete(ctx, userID, provider)
+}
+
+// List retorna las identidades vinculadas a la cuenta del usuario
+func (s *identityService) List(ctx context.Context, userID string) ([]*domain.UserIdentity, error) {
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+// link persiste el vínculo tolerando que un login concurrente con la misma identidad ya lo haya creado
+func (s *identityService) link(ctx context.Context, userID string, identity *domain.ExternalIdentity) error {
+	err := s.identityRepo.Create(ctx, &domain.UserIdentity{
+		Provider:        identity.Provider,
+		ProviderSubject: identity.Subject,
+		UserID:          userID,
+		EmailAtLink:     identity.Email,
+		LinkedAt:        time.Now(),
+	})
+	if !errors.Is(err, domain.ErrIdentityAlreadyLinked) {
+		return err
+	}
+
+	existing, getErr := s.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if getErr != nil || existing.UserID != userID {
+		return err
+	}
+	return nil
+}
+
+// recordLink registra en la auditoría el vínculo de una identidad externa con la cuenta
+func (s *identityService) recordLink(ctx context.Context, userID string, identity *domain.ExternalIdentity, reason string) {
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       domain.AuthEventIdentityLinked,
+		UserID:     userID,
+		Identifier: identity.Email,
+		Reason:     reason,
+		Metadata: map[string]string{
+			"provider":         identity.Provider,
+			"provider_subject": identity.Subject,
+		},
+		OccurredAt: time.Now(),
+	})
+}
+
+// provisionUser crea el usuario de una identidad verificada que todavía no tiene cuenta
+func (s *identityService) provisionUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	policy, ok := s.provisioning.Providers[identity.Provider]
+	if !ok || !policy.Enabled {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if !emailDomainAllowed(identity.Email, policy.AllowedEmailDomains) {
+		return nil, domain.ErrEmailDomainNotAllowed
+	}
+
+	nickName, err := s.uniqueNickName(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		ID:            uuid.NewString(),
+		NickName:      nickName,
+		FirstName:     identity.GivenName,
+		LastName:      identity.FamilyName,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		IsActive:      true,
+		Role:          s.provisioning.DefaultRole,
+		HasPassword:   false,
+	}
+	if err := s.userService.CreateUser(ctx, user); err != nil {
+		// Un login concurrente con el mismo email ya creó la cuenta
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			return s.userService.GetUserByEmail(ctx, identity.Email)
+		}
+		// Otro alta concurrente se quedó el nick_name derivado; un nuevo intento derivará otro
+		if errors.Is(err, domain.ErrNickNameTaken) {
+			validation := domain.NewValidationError()
+			validation.Add("nick_name", "is already taken")
+			return nil, validation
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// uniqueNickName deriva un nick_name libre a partir del email o del nombre,
+// añadiendo un sufijo numérico si ya está en uso
+func (s *identityService) uniqueNickName(ctx context.Context, identity *domain.ExternalIdentity) (string, error) {
+	base := sanitizeNickName(strings.SplitN(identity.Email, "@", 2)[0])
+	if base == "" {
+		base = sanitizeNickName(identity.GivenName + identity.FamilyName)
+	}
+	if base == "" {
+		base = "rider"
+	}
+
+	candidate := base
+	for i := 2; i <= maxNickNameAttempts+1; i++ {
+		exists, err := s.userService.ExistsByNickName(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+
+	return base + strings.ReplaceAll(uuid.NewString(), "-", "")[:8], nil
+}
+
+// sanitizeNickName deja solo letras, dígitos, puntos y guiones bajos en minúscula
+func sanitizeNickName(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Trim(b.String(), "._")
+}
+
+// emailDomainAllowed verifica si el dominio del email está entre los permitidos
+func emailDomainAllowed(email string, allowedDomains []string) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	emailDomain := email[at+1:]
+
+	for _, allowed := range allowedDomains {
+		if strings.EqualFold(emailDomain, allowed) {
+			return true
+		}
+	}
+	return false
+}
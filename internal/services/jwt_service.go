@@ -1,39 +1,75 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// JWTServiceConfig contiene la configuración del servicio JWT
+type JWTServiceConfig struct {
+	Issuer string
+	// ClientID y Scope se incluyen en los access tokens (RFC 9068)
+	ClientID               string
+	Scope                  string
+	AccessTokenExpiration  time.Duration
+	RefreshTokenExpiration time.Duration
+	AccessTokenAudience    string
+	RefreshTokenAudience   string
+	// MFAChallengeExpiration es la validez del challenge del login con segundo factor
+	MFAChallengeExpiration time.Duration
+}
+
 type jwtService struct {
-	secretKey              []byte
+	keyring                ports.Keyring
+	issuer                 string
+	clientID               string
+	scope                  string
 	accessTokenExpiration  time.Duration
 	refreshTokenExpiration time.Duration
+	mfaChallengeExpiration time.Duration
+	audiences              map[domain.TokenType]string
 }
 
 // NewJWTService crea una nueva instancia del servicio JWT
-func NewJWTService(secretKey string, accessTokenExpiration, refreshTokenExpiration time.Duration) ports.JWTService {
+func NewJWTService(keyring ports.Keyring, cfg JWTServiceConfig) ports.JWTService {
 	return &jwtService{
-		secretKey:              []byte(secretKey),
-		accessTokenExpiration:  accessTokenExpiration,
-		refreshTokenExpiration: refreshTokenExpiration,
+		keyring:                keyring,
+		issuer:                 cfg.Issuer,
+		clientID:               cfg.ClientID,
+		scope:                  cfg.Scope,
+		accessTokenExpiration:  cfg.AccessTokenExpiration,
+		refreshTokenExpiration: cfg.RefreshTokenExpiration,
+		mfaChallengeExpiration: cfg.MFAChallengeExpiration,
+		audiences: map[domain.TokenType]string{
+			domain.AccessToken:  cfg.AccessTokenAudience,
+			domain.RefreshToken: cfg.RefreshTokenAudience,
+			// El challenge solo lo acepta este servicio, nunca un resource server
+			domain.MFAChallengeToken: cfg.Issuer,
+		},
 	}
 }
 
 // GenerateTokenPair genera un par de tokens (access y refresh) para un usuario
-func (s *jwtService) GenerateTokenPair(user *domain.User) (*domain.TokenPair, error) {
+func (s *jwtService) GenerateTokenPair(user *domain.User, authCtx domain.AuthContext) (*domain.TokenPair, error) {
+	if authCtx.AuthTime.IsZero() {
+		authCtx.AuthTime = time.Now()
+	}
+
 	// Generar access token
-	accessToken, err := s.generateToken(user, domain.AccessToken, s.accessTokenExpiration)
+	accessToken, err := s.generateToken(user, authCtx, domain.AccessToken, s.accessTokenExpiration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generar refresh token
-	refreshToken, err := s.generateToken(user, domain.RefreshToken, s.refreshTokenExpiration)
+	refreshToken, err := s.generateToken(user, authCtx, domain.RefreshToken, s.refreshTokenExpiration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -46,35 +82,66 @@ func (s *jwtService) GenerateTokenPair(user *domain.User) (*domain.TokenPair, er
 	}, nil
 }
 
+// GenerateMFAChallenge genera el token de un solo propósito con el que se completa el login con segundo factor.
+// Conserva el contexto del primer factor para incluirlo en los tokens finales.
+func (s *jwtService) GenerateMFAChallenge(user *domain.User, authCtx domain.AuthContext) (*domain.MFAChallenge, error) {
+	token, err := s.generateToken(user, authCtx, domain.MFAChallengeToken, s.mfaChallengeExpiration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mfa challenge: %w", err)
+	}
+
+	return &domain.MFAChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(s.mfaChallengeExpiration.Seconds()),
+	}, nil
+}
+
 // generateToken genera un token JWT
-func (s *jwtService) generateToken(user *domain.User, tokenType domain.TokenType, expiration time.Duration) (string, error) {
+func (s *jwtService) generateToken(user *domain.User, authCtx domain.AuthContext, tokenType domain.TokenType, expiration time.Duration) (string, error) {
 	now := time.Now()
 	expirationTime := now.Add(expiration)
+	tokenID := uuid.NewString()
 
 	claims := &domain.JWTClaims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		NickName: user.NickName,
-		Role:     user.Role,
+		UserID:        user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		NickName:      user.NickName,
+		Role:          user.Role,
+		TokenType:     tokenType,
+		AuthTime:      authCtx.AuthTime.Unix(),
+		AMR:           authCtx.Methods,
+		ACR:           authCtx.ACR(),
+		SessionID:     authCtx.SessionID,
 		// Campos explícitos para swagger
 		ExpiresAt: expirationTime.Unix(),
 		IssuedAt:  now.Unix(),
 		NotBefore: now.Unix(),
-		Issuer:    "bikes2road-auth",
-		ID:        fmt.Sprintf("%s-%s-%d", user.ID, tokenType, now.Unix()),
+		Issuer:    s.issuer,
+		ID:        tokenID,
 		// También llenar RegisteredClaims para compatibilidad con jwt library
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "bikes2road-auth",
+			Issuer:    s.issuer,
 			Subject:   user.ID,
-			ID:        fmt.Sprintf("%s-%s-%d", user.ID, tokenType, now.Unix()),
+			ID:        tokenID,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.secretKey)
+	if audience := s.audiences[tokenType]; audience != "" {
+		claims.RegisteredClaims.Audience = jwt.ClaimStrings{audience}
+	}
+	if tokenType == domain.AccessToken {
+		claims.ClientID = s.clientID
+		claims.Scope = s.scope
+	}
+
+	key := s.keyring.ActiveKey()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.Kid
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -82,7 +149,8 @@ func (s *jwtService) generateToken(user *domain.User, tokenType domain.TokenType
 	return tokenString, nil
 }
 
-// ValidateToken valida un token y retorna sus claims
+// ValidateToken valida un token y retorna sus claims.
+// El token debe ser del tipo indicado y estar emitido para la audiencia de ese tipo.
 func (s *jwtService) ValidateToken(tokenString string, tokenType domain.TokenType) (*domain.JWTClaims, error) {
 	claims, err := s.ParseToken(tokenString)
 	if err != nil {
@@ -94,23 +162,31 @@ func (s *jwtService) ValidateToken(tokenString string, tokenType domain.TokenTyp
 		return nil, domain.ErrTokenExpired
 	}
 
+	if claims.Issuer != s.issuer {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Un refresh token no puede usarse como access token ni viceversa
+	if claims.TokenType != tokenType {
+		return nil, domain.ErrInvalidTokenType
+	}
+
+	if audience := s.audiences[tokenType]; audience != "" && !slices.Contains(claims.Audience, audience) {
+		return nil, domain.ErrInvalidTokenAudience
+	}
+
 	return claims, nil
 }
 
 // ParseToken parsea un token y retorna sus claims sin validar expiración
 func (s *jwtService) ParseToken(tokenString string) (*domain.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verificar que el método de firma sea el esperado
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.secretKey, nil
-	})
+	// Solo se aceptan los algoritmos de las claves vigentes, lo que descarta "none" y la confusión HS256/RS256
+	token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{}, s.verificationKey, jwt.WithValidMethods(s.validMethods()))
 
 	if err != nil {
-		if err == jwt.ErrTokenMalformed {
+		if errors.Is(err, jwt.ErrTokenMalformed) {
 			return nil, domain.ErrTokenMalformed
-		} else if err == jwt.ErrTokenExpired {
+		} else if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, domain.ErrTokenExpired
 		}
 		return nil, domain.ErrInvalidToken
@@ -123,3 +199,57 @@ func (s *jwtService) ParseToken(tokenString string) (*domain.JWTClaims, error) {
 
 	return claims, nil
 }
+
+// JWKS retorna las claves públicas de verificación vigentes.
+// Las claves HS256 nunca se publican: son secretos compartidos.
+func (s *jwtService) JWKS() (*domain.JWKS, error) {
+	jwks := &domain.JWKS{Keys: []domain.JWK{}}
+	for _, key := range s.keyring.Keys() {
+		if key.IsSymmetric() {
+			continue
+		}
+
+		jwk, err := domain.NewJWK(key.Kid, key.Algorithm, key.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build jwk: %w", err)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks, nil
+}
+
+// Issuer retorna el emisor (iss) de los tokens
+func (s *jwtService) Issuer() string {
+	return s.issuer
+}
+
+// verificationKey selecciona la clave de verificación por el kid de la cabecera.
+// Los tokens sin kid, emitidos antes del keyring, se verifican con la clave activa.
+func (s *jwtService) verificationKey(token *jwt.Token) (interface{}, error) {
+	key := s.keyring.ActiveKey()
+	if kid, ok := token.Header["kid"].(string); ok {
+		var err error
+		if key, err = s.keyring.VerificationKey(kid); err != nil {
+			return nil, err
+		}
+	}
+
+	// El algoritmo del token debe coincidir con el de la clave seleccionada
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
+
+// validMethods retorna los algoritmos de las claves vigentes
+func (s *jwtService) validMethods() []string {
+	var methods []string
+	for _, key := range s.keyring.Keys() {
+		if !slices.Contains(methods, key.Algorithm) {
+			methods = append(methods, key.Algorithm)
+		}
+	}
+	return methods
+}
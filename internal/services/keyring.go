@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// keyringRefreshCooldown es el tiempo mínimo entre recargas provocadas por un kid desconocido
+const keyringRefreshCooldown = 10 * time.Second
+
+// KeyringConfig contiene la configuración inicial del keyring
+type KeyringConfig struct {
+	// Algorithm es HS256, RS256, ES256 o EdDSA y determina el tipo de las claves nuevas
+	Algorithm string
+	// SecretKey es la clave activa con HS256
+	SecretKey string
+	// PrivateKeyPEM es la clave activa con RS256, ES256 o EdDSA
+	PrivateKeyPEM []byte
+	// RetiredSecretKeys y RetiredPrivateKeyPEMs siguen verificando tokens durante GracePeriod
+	RetiredSecretKeys     []string
+	RetiredPrivateKeyPEMs [][]byte
+	// GracePeriod es el tiempo que una clave retirada sigue verificando tokens
+	GracePeriod time.Duration
+	// EncryptionKey es la clave AES-256 (base64) con la que se guardan cifradas las claves generadas al rotar
+	EncryptionKey string
+}
+
+// keyring mantiene las claves de firma: una activa y las retiradas en periodo de gracia.
+// El estado vive en la base de datos para que todas las réplicas firmen y verifiquen con las mismas claves
+// y sobreviva a los reinicios; cada réplica lo recarga periódicamente.
+type keyring struct {
+	mu          sync.RWMutex
+	repo        ports.SigningKeyRepository
+	cipher      *secretCipher
+	algorithm   string
+	gracePeriod time.Duration
+	// configured son las claves de la configuración; su material no se guarda en la base de datos
+	configured  map[string]*domain.SigningKey
+	active      *domain.SigningKey
+	retired     []*domain.SigningKey
+	refreshedAt time.Time
+}
+
+// NewKeyring registra las claves de la configuración y carga el estado compartido del keyring.
+// La clave activa de la configuración solo sustituye a la almacenada si es nueva, de modo que reiniciar
+// con la misma configuración no deshace una rotación hecha desde la API.
+func NewKeyring(ctx context.Context, repo ports.SigningKeyRepository, cfg KeyringConfig) (ports.Keyring, error) {
+	cipher, err := newSecretCipher(cfg.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key encryption key: %w", err)
+	}
+
+	now := time.Now()
+	active, err := loadSigningKey(cfg.Algorithm, cfg.SecretKey, cfg.PrivateKeyPEM, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active signing key: %w", err)
+	}
+
+	k := &keyring{
+		repo:        repo,
+		cipher:      cipher,
+		algorithm:   cfg.Algorithm,
+		gracePeriod: cfg.GracePeriod,
+		configured:  map[string]*domain.SigningKey{active.Kid: active},
+	}
+
+	if err := repo.Activate(ctx, signingKeyRecord(active, ""), now.Add(cfg.GracePeriod)); err != nil {
+		return nil, err
+	}
+
+	var retired []*domain.SigningKey
+	for _, secret := range cfg.RetiredSecretKeys {
+		key, err := loadSigningKey(jwt.SigningMethodHS256.Alg(), secret, nil, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load retired signing key: %w", err)
+		}
+		retired = append(retired, key)
+	}
+	for _, privateKeyPEM := range cfg.RetiredPrivateKeyPEMs {
+		algorithm, err := detectAlgorithm(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load retired signing key: %w", err)
+		}
+		key, err := loadSigningKey(algorithm, "", privateKeyPEM, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load retired signing key: %w", err)
+		}
+		retired = append(retired, key)
+	}
+
+	// El fin del periodo de gracia se fija la primera vez que se ve la clave retirada y se respeta en los reinicios
+	for _, key := range retired {
+		k.configured[key.Kid] = key
+		if err := repo.Retire(ctx, signingKeyRecord(key, ""), now.Add(cfg.GracePeriod)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := k.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// ActiveKey retorna la clave con la que se firman los nuevos tokens
+func (k *keyring) ActiveKey() *domain.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// VerificationKey retorna la clave con el kid indicado si todavía puede verificar tokens.
+// Un kid desconocido puede ser de una rotación hecha en otra réplica, por lo que recarga el keyring
+// antes de rechazarlo, como mucho una vez cada keyringRefreshCooldown.
+func (k *keyring) VerificationKey(kid string) (*domain.SigningKey, error) {
+	if key := k.findKey(kid); key != nil {
+		return key, nil
+	}
+	if k.reserveRefresh() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := k.Refresh(ctx); err != nil {
+			log.Printf("failed to refresh signing keys: %v", err)
+		}
+		if key := k.findKey(kid); key != nil {
+			return key, nil
+		}
+	}
+	return nil, domain.ErrSigningKeyNotFound
+}
+
+// Keys retorna las claves vigentes, empezando por la activa
+func (k *keyring) Keys() []*domain.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	keys := []*domain.SigningKey{k.active}
+	for _, key := range k.retired {
+		if key.CanVerify(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Rotate genera una nueva clave activa, la guarda cifrada y retira la anterior durante el periodo de gracia.
+// El resto de réplicas la adoptan en su siguiente recarga o al recibir un token firmado con ella.
+func (k *keyring) Rotate(ctx context.Context) (*domain.SigningKey, error) {
+	now := time.Now()
+	key, err := generateSigningKey(k.algorithm, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	material, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := k.cipher.Encrypt(material, key.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	if err := k.repo.Activate(ctx, signingKeyRecord(key, encrypted), now.Add(k.gracePeriod)); err != nil {
+		return nil, err
+	}
+	if err := k.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Refresh recarga las claves almacenadas. Las claves que esta réplica no puede usar (de la configuración
+// de otra réplica o que no se pueden descifrar) se omiten; si no queda una clave activa se conserva el estado anterior.
+func (k *keyring) Refresh(ctx context.Context) error {
+	records, err := k.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var active *domain.SigningKey
+	var retired []*domain.SigningKey
+	for _, record := range records {
+		if record.Status != domain.SigningKeyActive && record.VerifyUntil != nil && !now.Before(*record.VerifyUntil) {
+			continue
+		}
+
+		// Una clave de la configuración que esta réplica ya no tiene configurada deja de verificar aquí
+		if _, ok := k.configured[record.Kid]; !ok && record.EncryptedKey == "" {
+			continue
+		}
+
+		key, err := k.restore(record)
+		if err != nil {
+			log.Printf("skipping signing key %s: %v", record.Kid, err)
+			continue
+		}
+
+		// Los registros llegan del más reciente al más antiguo: la clave activa es la última activada
+		if record.Status == domain.SigningKeyActive && active == nil {
+			active = key
+			continue
+		}
+		retired = append(retired, key)
+	}
+	if active == nil {
+		return errors.New("no usable active signing key is stored")
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active = active
+	k.retired = retired
+	k.refreshedAt = now
+	return nil
+}
+
+// findKey busca una clave vigente por su kid
+func (k *keyring) findKey(kid string) *domain.SigningKey {
+	for _, key := range k.Keys() {
+		if key.Kid == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// reserveRefresh indica si se puede recargar el keyring por un kid desconocido y, en ese caso,
+// reserva la recarga para que las peticiones concurrentes no la repitan
+func (k *keyring) reserveRefresh() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if time.Since(k.refreshedAt) < keyringRefreshCooldown {
+		return false
+	}
+	k.refreshedAt = time.Now()
+	return true
+}
+
+// restore reconstruye la clave de un registro con el material de la configuración o el descifrado de la base de datos
+func (k *keyring) restore(record *domain.SigningKeyRecord) (*domain.SigningKey, error) {
+	var key domain.SigningKey
+	if record.EncryptedKey == "" {
+		configured, ok := k.configured[record.Kid]
+		if !ok {
+			return nil, errors.New("key is not configured in this instance")
+		}
+		key = *configured
+	} else {
+		material, err := k.cipher.Decrypt(record.EncryptedKey, record.Kid)
+		if err != nil {
+			return nil, err
+		}
+		restored, err := decodePrivateKey(record.Algorithm, material, record.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		key = *restored
+	}
+
+	key.Kid = record.Kid
+	key.Status = record.Status
+	key.CreatedAt = record.CreatedAt
+	key.RetiredAt = record.RetiredAt
+	key.VerifyUntil = record.VerifyUntil
+	if key.Status == domain.SigningKeyActive {
+		key.RetiredAt = nil
+		key.VerifyUntil = nil
+	}
+	return &key, nil
+}
+
+// RunKeyringRefresh recarga periódicamente el keyring para adoptar las rotaciones hechas en otras réplicas.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunKeyringRefresh(ctx context.Context, keyring ports.Keyring, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := keyring.Refresh(ctx); err != nil {
+				log.Printf("failed to refresh signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// signingKeyRecord construye el registro persistible de una clave
+func signingKeyRecord(key *domain.SigningKey, encryptedKey string) *domain.SigningKeyRecord {
+	return &domain.SigningKeyRecord{
+		Kid:          key.Kid,
+		Algorithm:    key.Algorithm,
+		Status:       domain.SigningKeyActive,
+		EncryptedKey: encryptedKey,
+		CreatedAt:    key.CreatedAt,
+	}
+}
+
+// encodePrivateKey serializa el material privado de una clave generada: el secreto en HS256 y PKCS#8 PEM en el resto
+func encodePrivateKey(key *domain.SigningKey) (string, error) {
+	if key.IsSymmetric() {
+		secret, ok := key.PrivateKey.([]byte)
+		if !ok {
+			return "", errors.New("invalid HS256 secret")
+		}
+		return string(secret), nil
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// decodePrivateKey reconstruye una clave serializada con encodePrivateKey
+func decodePrivateKey(algorithm, material string, createdAt time.Time) (*domain.SigningKey, error) {
+	if algorithm == jwt.SigningMethodHS256.Alg() {
+		return loadSigningKey(algorithm, material, nil, createdAt)
+	}
+	return loadSigningKey(algorithm, "", []byte(material), createdAt)
+}
+
+// loadSigningKey construye una clave a partir del secreto (HS256) o de la clave privada PEM
+func loadSigningKey(algorithm, secret string, privateKeyPEM []byte, now time.Time) (*domain.SigningKey, error) {
+	key := &domain.SigningKey{
+		Algorithm: algorithm,
+		Status:    domain.SigningKeyActive,
+		CreatedAt: now,
+	}
+
+	switch algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if secret == "" {
+			return nil, fmt.Errorf("a secret key is required for %s", algorithm)
+		}
+		key.PrivateKey = []byte(secret)
+		key.PublicKey = []byte(secret)
+		// El kid se deriva del secreto para que todas las réplicas con el mismo secreto coincidan
+		sum := sha256.Sum256([]byte("bikes2road-kid:" + secret))
+		key.Kid = "hs256-" + hex.EncodeToString(sum[:8])
+		return key, nil
+
+	case jwt.SigningMethodRS256.Alg():
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		if private.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+		key.PrivateKey = private
+		key.PublicKey = &private.PublicKey
+
+	case jwt.SigningMethodES256.Alg():
+		private, err := jwt.ParseECPrivateKeyFromPEM(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		if private.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s requires a P-256 key", algorithm)
+		}
+		key.PrivateKey = private
+		key.PublicKey = &private.PublicKey
+
+	case jwt.SigningMethodEdDSA.Alg():
+		private, err := jwt.ParseEdPrivateKeyFromPEM(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("invalid Ed25519 private key")
+		}
+		key.PrivateKey = private
+		key.PublicKey = signer.Public()
+
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	kid, err := publicKeyThumbprint(algorithm, key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key.Kid = kid
+
+	return key, nil
+}
+
+// generateSigningKey genera una clave nueva del algoritmo indicado
+func generateSigningKey(algorithm string, now time.Time) (*domain.SigningKey, error) {
+	key := &domain.SigningKey{
+		Algorithm: algorithm,
+		Status:    domain.SigningKeyActive,
+		CreatedAt: now,
+	}
+
+	switch algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		key.PrivateKey = secret
+		key.PublicKey = secret
+		key.Kid = "hs256-" + uuid.NewString()
+		return key, nil
+
+	case jwt.SigningMethodRS256.Alg():
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		key.PrivateKey = private
+		key.PublicKey = &private.PublicKey
+
+	case jwt.SigningMethodES256.Alg():
+		private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key.PrivateKey = private
+		key.PublicKey = &private.PublicKey
+
+	case jwt.SigningMethodEdDSA.Alg():
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key.PrivateKey = private
+		key.PublicKey = public
+
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	kid, err := publicKeyThumbprint(algorithm, key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key.Kid = kid
+
+	return key, nil
+}
+
+// publicKeyThumbprint identifica una clave asimétrica por su thumbprint JWK (RFC 7638)
+func publicKeyThumbprint(algorithm string, publicKey crypto.PublicKey) (string, error) {
+	jwk, err := domain.NewJWK("", algorithm, publicKey)
+	if err != nil {
+		return "", err
+	}
+	kid, err := jwk.Thumbprint()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute key id: %w", err)
+	}
+	return kid, nil
+}
+
+// detectAlgorithm deduce el algoritmo de firma a partir del tipo de la clave privada PEM
+func detectAlgorithm(privateKeyPEM []byte) (string, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return "", fmt.Errorf("invalid PEM private key")
+	}
+
+	var private any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		private, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256.Alg(), nil
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return "", fmt.Errorf("only P-256 EC keys are supported")
+		}
+		return jwt.SigningMethodES256.Alg(), nil
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA.Alg(), nil
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", private)
+	}
+}
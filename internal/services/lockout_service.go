@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// LockoutConfig contiene la política de bloqueo de cuentas tras logins fallidos
+type LockoutConfig struct {
+	// Threshold es el número de fallos consecutivos a partir del cual se bloquea la cuenta
+	Threshold int
+	// BaseDelay es la duración del primer bloqueo; cada fallo adicional la duplica
+	BaseDelay time.Duration
+	// MaxDelay limita la duración de un bloqueo
+	MaxDelay time.Duration
+	// ResetAfter reinicia el contador si el último fallo es más antiguo
+	ResetAfter time.Duration
+}
+
+type lockoutService struct {
+	repo        ports.LoginAttemptRepository
+	auditLogger ports.AuditLogger
+	config      LockoutConfig
+}
+
+// NewLockoutService crea una nueva instancia del servicio de bloqueo de cuentas
+func NewLockoutService(repo ports.LoginAttemptRepository, auditLogger ports.AuditLogger, config LockoutConfig) ports.LockoutService {
+	return &lockoutService{
+		repo:        repo,
+		auditLogger: auditLogger,
+		config:      config,
+	}
+}
+
+// Check retorna un *domain.AccountLockedError si la cuenta está bloqueada
+func (s *lockoutService) Check(ctx context.Context, userID string) error {
+	attempts, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check account lockout: %w", err)
+	}
+	if attempts == nil || attempts.LockedUntil == nil {
+		return nil
+	}
+
+	if retryAfter := time.Until(*attempts.LockedUntil); retryAfter > 0 {
+		return &domain.AccountLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure registra un login fallido y bloquea la cuenta si se alcanza el umbral.
+// Retorna un *domain.AccountLockedError cuando este fallo provoca el bloqueo.
+func (s *lockoutService) RecordFailure(ctx context.Context, userID string) error {
+	now := time.Now()
+	attempts, err := s.repo.RecordFailure(ctx, userID, now.Add(-s.config.ResetAfter))
+	if err != nil {
+		return fmt.Errorf("failed to record login failure: %w", err)
+	}
+	if attempts.FailedCount < s.config.Threshold {
+		return nil
+	}
+
+	delay := s.lockDelay(attempts.FailedCount)
+	if err := s.repo.Lock(ctx, userID, now.Add(delay)); err != nil {
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:   domain.AuthEventAccountLocked,
+		UserID: userID,
+		Reason: "too many failed login attempts",
+		Metadata: map[string]string{
+			"failed_count": strconv.Itoa(attempts.FailedCount),
+			"locked_for":   delay.String(),
+		},
+		OccurredAt: now,
+	})
+
+	return &domain.AccountLockedError{RetryAfter: delay}
+}
+
+// RecordSuccess reinicia el contador de fallos tras un login correcto
+func (s *lockoutService) RecordSuccess(ctx context.Context, userID string) {
+	if err := s.repo.Reset(ctx, userID); err != nil {
+		log.Printf("failed to reset login attempts for user %s: %v", userID, err)
+	}
+}
+
+// Unlock desbloquea la cuenta y reinicia su contador de fallos
+func (s *lockoutService) Unlock(ctx context.Context, userID, actorID string) error {
+	if err := s.repo.Reset(ctx, userID); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       domain.AuthEventAccountUnlocked,
+		UserID:     userID,
+		Reason:     "unlocked by an administrator",
+		Metadata:   map[string]string{"actor_id": actorID},
+		OccurredAt: time.Now(),
+	})
+	return nil
+}
+
+// lockDelay calcula el bloqueo con backoff exponencial: BaseDelay * 2^(fallos - Threshold), hasta MaxDelay
+func (s *lockoutService) lockDelay(failedCount int) time.Duration {
+	delay := s.config.BaseDelay
+	for i := s.config.Threshold; i < failedCount; i++ {
+		delay *= 2
+		if delay >= s.config.MaxDelay {
+			return s.config.MaxDelay
+		}
+	}
+	return min(delay, s.config.MaxDelay)
+}
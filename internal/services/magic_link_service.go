@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// MagicLinkServiceConfig contiene la configuración del login con enlaces enviados por email
+type MagicLinkServiceConfig struct {
+	// URL es la dirección a la que apunta el enlace; se le añade ?token=...
+	URL string
+	// TokenTTL es la validez del enlace
+	TokenTTL time.Duration
+	// EmailLimit limita los enlaces enviados a una misma dirección; sin configurar no se aplica
+	EmailLimit domain.RateLimit
+	// SigningKey es la clave HMAC-SHA256 (32 bytes en base64) con la que se firman los enlaces
+	SigningKey string
+}
+
+type magicLinkService struct {
+	userService ports.UserService
+	tokenRepo   ports.OneTimeTokenRepository
+	mailer      ports.Mailer
+	limiter     ports.RateLimiter
+	signingKey  []byte
+	config      MagicLinkServiceConfig
+}
+
+// NewMagicLinkService crea una nueva instancia del servicio de login por enlace
+func NewMagicLinkService(
+	userService ports.UserService,
+	tokenRepo ports.OneTimeTokenRepository,
+	mailer ports.Mailer,
+	limiter ports.RateLimiter,
+	config MagicLinkServiceConfig,
+) (ports.MagicLinkService, error) {
+	signingKey, err := base64.StdEncoding.DecodeString(config.SigningKey)
+	if err != nil || len(signingKey) != 32 {
+		return nil, fmt.Errorf("magic link signing key must be a base64 encoded 32-byte key")
+	}
+
+	return &magicLinkService{
+		userService: userService,
+		tokenRepo:   tokenRepo,
+		mailer:      mailer,
+		limiter:     limiter,
+		signingKey:  signingKey,
+		config:      config,
+	}, nil
+}
+
+// Request envía un enlace de login si la cuenta existe y retorna el nonce que liga el enlace al navegador.
+// El envío se hace en segundo plano para que la respuesta (y su duración) sea la misma exista o no la cuenta.
+func (s *magicLinkService) Request(ctx context.Context, email string) (string, error) {
+	// En minúsculas para que el límite por dirección no se esquive cambiando mayúsculas;
+	// la búsqueda de la cuenta tampoco las distingue, así que encuentra las guardadas con ellas
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	nonce, err := newRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	// El envío sobrevive a la respuesta pero conserva los valores de la petición
+	// (request ID, IP y User-Agent) para los eventos de auditoría
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		if err := s.sendLink(ctx, email, nonce); err != nil {
+			log.Printf("failed to send magic link email: %v", err)
+		}
+	}()
+
+	return nonce, nil
+}
+
+// Consume valida el enlace con el nonce del navegador que lo solicitó y retorna el usuario al que pertenece
+func (s *magicLinkService) Consume(ctx context.Context, token, nonce string) (*domain.User, error) {
+	if nonce == "" {
+		return nil, domain.ErrMagicLinkBrowserMismatch
+	}
+
+	// Un enlace alterado o caducado se rechaza sin consultar la base de datos
+	if !s.verifyLink(token, time.Now()) {
+		return nil, domain.ErrOneTimeTokenInvalid
+	}
+
+	record, err := s.tokenRepo.Consume(ctx, domain.PurposeMagicLink, magicLinkHash(token, nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetUserByID(ctx, record.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	// Los enlaces pendientes de otros navegadores dejan de valer al iniciar sesión con uno
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, domain.PurposeMagicLink); err != nil {
+		return nil, err
+	}
+
+	// Abrir el enlace demuestra el control del buzón
+	if !user.EmailVerified {
+		user.EmailVerified = true
+		if err := s.userService.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// sendLink emite un token ligado al nonce y lo envía por email, respetando el límite por dirección
+func (s *magicLinkService) sendLink(ctx context.Context, email, nonce string) error {
+	// El límite se aplica exista o no la cuenta y sin avisar al cliente, que siempre recibe la misma respuesta
+	if s.config.EmailLimit.Enabled() {
+		result, err := s.limiter.Allow(ctx, "magic-link:"+hashToken(email), s.config.EmailLimit)
+		if err != nil {
+			log.Printf("rate limiter unavailable, sending magic link: %v", err)
+		} else if !result.Allowed {
+			return nil
+		}
+	}
+
+	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	random, record, err := newOneTimeToken(domain.PurposeMagicLink, user.ID, s.config.TokenTTL)
+	if err != nil {
+		return err
+	}
+	token := s.signLink(random, record.ExpiresAt)
+	record.TokenHash = magicLinkHash(token, nonce)
+
+	if err := s.tokenRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	link := s.config.URL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Tu enlace para entrar en Bikes2Road",
+		Body: fmt.Sprintf("Hola %s,\n\nPara entrar en Bikes2Road abre este enlace en el mismo navegador desde el que lo has solicitado:\n\n%s\n\nEl enlace caduca en %s y solo puede usarse una vez. Si no lo has solicitado, ignora este mensaje.\n",
+			user.FirstName, link, s.config.TokenTTL),
+	})
+}
+
+// signLink compone el token del enlace: el valor aleatorio, su caducidad y la firma HMAC de ambos.
+// La firma permite rechazar enlaces falsificados o caducados antes de buscarlos; el uso único y el
+// vínculo con el navegador siguen dependiendo del registro en one_time_tokens.
+func (s *magicLinkService) signLink(random string, expiresAt time.Time) string {
+	payload := random + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.linkSignature(payload)
+}
+
+// verifyLink comprueba la firma y la caducidad de un token generado por signLink
+func (s *magicLinkService) verifyLink(token string, now time.Time) bool {
+	separator := strings.LastIndexByte(token, '.')
+	if separator < 0 {
+		return false
+	}
+	payload, signature := token[:separator], token[separator+1:]
+	if !hmac.Equal([]byte(signature), []byte(s.linkSignature(payload))) {
+		return false
+	}
+
+	_, expiry, ok := strings.Cut(payload, ".")
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	return err == nil && now.Unix() < expiresAt
+}
+
+// linkSignature firma el payload con un prefijo propio para que la firma no sirva en otro contexto
+func (s *magicLinkService) linkSignature(payload string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte("magic-link." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// magicLinkHash liga el token del enlace al nonce de la cookie: el registro solo se encuentra con ambos valores.
+func magicLinkHash(token, nonce string) string {
+	return hashToken(token + "." + nonce)
+}
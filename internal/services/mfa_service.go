@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	// recoveryCodeAlphabet evita caracteres ambiguos (0/o, 1/l/i)
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+	qrCodeSize           = 256
+)
+
+// MFAServiceConfig contiene la configuración del segundo factor TOTP
+type MFAServiceConfig struct {
+	// Issuer es el nombre que muestran las apps de autenticación
+	Issuer string
+	// EncryptionKey es la clave AES-256 (base64) con la que se cifran los secretos TOTP
+	EncryptionKey string
+}
+
+type mfaService struct {
+	userService ports.UserService
+	repo        ports.MFARepository
+	passkeys    ports.PasskeyRepository
+	lockout     ports.LockoutService
+	cipher      *secretCipher
+	auditLogger ports.AuditLogger
+	issuer      string
+}
+
+// NewMFAService crea una nueva instancia del servicio de segundo factor
+func NewMFAService(
+	userService ports.UserService,
+	repo ports.MFARepository,
+	passkeys ports.PasskeyRepository,
+	lockout ports.LockoutService,
+	auditLogger ports.AuditLogger,
+	config MFAServiceConfig,
+) (ports.MFAService, error) {
+	cipher, err := newSecretCipher(config.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mfa encryption key: %w", err)
+	}
+
+	return &mfaService{
+		userService: userService,
+		repo:        repo,
+		passkeys:    passkeys,
+		lockout:     lockout,
+		cipher:      cipher,
+		auditLogger: auditLogger,
+		issuer:      config.Issuer,
+	}, nil
+}
+
+// Status retorna si el usuario tiene el segundo factor TOTP activo, cuántos códigos de recuperación le quedan
+// y cuántas passkeys tiene registradas
+func (s *mfaService) Status(ctx context.Context, userID string) (*domain.MFAStatusResponse, error) {
+	status := &domain.MFAStatusResponse{}
+
+	factor, err := s.repo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrMFANotEnrolled) {
+		return nil, err
+	}
+	if err == nil && factor.Enabled {
+		status.Enabled = true
+		status.RecoveryCodesRemaining = len(factor.RecoveryCodeHashes)
+	}
+
+	status.Passkeys, err = s.passkeys.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Enroll genera un secreto TOTP pendiente de confirmar. Repetirlo sustituye el registro pendiente anterior.
+func (s *mfaService) Enroll(ctx context.Context, userID string) (*domain.MFAEnrollmentResponse, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.cipher.Encrypt(secret, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SavePending(ctx, &domain.MFAFactor{
+		UserID:          userID,
+		EncryptedSecret: encrypted,
+		CreatedAt:       time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	uri := totpURI(s.issuer, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate qr code: %w", err)
+	}
+
+	return &domain.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// Confirm activa el segundo factor con el primer código generado por la app y retorna los códigos de recuperación
+func (s *mfaService) Confirm(ctx context.Context, userID, code string) (*domain.MFARecoveryCodesResponse, error) {
+	factor, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if factor.Enabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := s.cipher.Decrypt(factor.EncryptedSecret, userID)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := validateTOTP(secret, normalizeMFACode(code), time.Now())
+	if !ok {
+		return nil, invalidMFACodeError()
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Enable(ctx, userID, hashes, step); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, domain.AuthEventMFAEnabled, userID, "totp confirmed")
+	return &domain.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable desactiva el segundo factor tras comprobar un código TOTP o de recuperación
+func (s *mfaService) Disable(ctx context.Context, userID, code string) error {
+	if err := s.verifyCode(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	s.record(ctx, domain.AuthEventMFADisabled, userID, "disabled by the user")
+	return nil
+}
+
+// RegenerateRecoveryCodes invalida los códigos de recuperación y genera un lote nuevo tras comprobar un código
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) (*domain.MFARecoveryCodesResponse, error) {
+	if err := s.verifyCode(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, domain.AuthEventRecoveryCodesRegenerated, userID, "regenerated by the user")
+	return &domain.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// verifyCode comprueba el código que autoriza una operación sobre el segundo factor. Los códigos incorrectos
+// cuentan para el bloqueo de la cuenta igual que en LoginMFA, para que un access token robado no permita probarlos.
+func (s *mfaService) verifyCode(ctx context.Context, userID, code string) error {
+	if err := s.lockout.Check(ctx, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.Verify(ctx, userID, code); err != nil {
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			return err
+		}
+		if err := s.lockout.RecordFailure(ctx, userID); err != nil {
+			var lockedErr *domain.AccountLockedError
+			if errors.As(err, &lockedErr) {
+				return err
+			}
+			log.Printf("failed to record mfa failure for user %s: %v", userID, err)
+		}
+		return invalidMFACodeError()
+	}
+
+	s.lockout.RecordSuccess(ctx, userID)
+	return nil
+}
+
+// Factors retorna los factores disponibles para completar un challenge: TOTP y, si quedan, códigos de recuperación,
+// y las passkeys registradas
+func (s *mfaService) Factors(ctx context.Context, userID string) ([]string, error) {
+	var factors []string
+
+	factor, err := s.repo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrMFANotEnrolled) {
+		return nil, err
+	}
+	if err == nil && factor.Enabled {
+		factors = append(factors, domain.FactorTOTP)
+		if len(factor.RecoveryCodeHashes) > 0 {
+			factors = append(factors, domain.FactorRecoveryCode)
+		}
+	}
+
+	passkeys, err := s.passkeys.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if passkeys > 0 {
+		factors = append(factors, domain.FactorWebAuthn)
+	}
+	return factors, nil
+}
+
+// Verify comprueba un código TOTP (no usado antes) o consume un código de recuperación y retorna el factor usado.
+// Retorna domain.ErrMFANotEnabled si el usuario no tiene el segundo factor activo.
+func (s *mfaService) Verify(ctx context.Context, userID, code string) (string, error) {
+	factor, err := s.repo.Get(ctx, userID)
+	if errors.Is(err, domain.ErrMFANotEnrolled) {
+		return "", domain.ErrMFANotEnabled
+	}
+	if err != nil {
+		return "", err
+	}
+	if !factor.Enabled {
+		return "", domain.ErrMFANotEnabled
+	}
+
+	code = normalizeMFACode(code)
+	if len(code) == totpDigits {
+		return domain.FactorTOTP, s.verifyTOTP(ctx, factor, code)
+	}
+	return domain.FactorRecoveryCode, s.consumeRecoveryCode(ctx, userID, code)
+}
+
+func (s *mfaService) verifyTOTP(ctx context.Context, factor *domain.MFAFactor, code string) error {
+	secret, err := s.cipher.Decrypt(factor.EncryptedSecret, factor.UserID)
+	if err != nil {
+		return err
+	}
+
+	step, ok := validateTOTP(secret, code, time.Now())
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+
+	// Un código solo sirve una vez: el intervalo debe ser posterior al último aceptado
+	fresh, err := s.repo.UseStep(ctx, factor.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *mfaService) consumeRecoveryCode(ctx context.Context, userID, code string) error {
+	if len(code) != recoveryCodeLength {
+		return domain.ErrInvalidMFACode
+	}
+
+	consumed, err := s.repo.ConsumeRecoveryCode(ctx, userID, hashToken(code))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return domain.ErrInvalidMFACode
+	}
+
+	s.record(ctx, domain.AuthEventRecoveryCodeUsed, userID, "recovery code consumed")
+	return nil
+}
+
+func (s *mfaService) record(ctx context.Context, eventType domain.AuthEventType, userID, reason string) {
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       eventType,
+		UserID:     userID,
+		Reason:     reason,
+		OccurredAt: time.Now(),
+	})
+}
+
+// newRecoveryCodes genera los códigos de recuperación en claro (formato xxxxx-xxxxx) y sus hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, domain.RecoveryCodeCount)
+	hashes := make([]string, 0, domain.RecoveryCodeCount)
+
+	raw := make([]byte, recoveryCodeLength)
+	for range domain.RecoveryCodeCount {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := make([]byte, recoveryCodeLength)
+		for i, b := range raw {
+			// 256 no es múltiplo del alfabeto; el sesgo resultante es despreciable para 50 bits de entropía
+			code[i] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+		}
+
+		half := recoveryCodeLength / 2
+		codes = append(codes, string(code[:half])+"-"+string(code[half:]))
+		hashes = append(hashes, hashToken(string(code)))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeMFACode elimina separadores y espacios y pasa a minúsculas los códigos de recuperación
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+// invalidMFACodeError informa el código incorrecto como error de validación en los endpoints de gestión
+func invalidMFACodeError() error {
+	validation := domain.NewValidationError()
+	validation.Add("code", "is invalid or has already been used")
+	return validation
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/google/uuid"
+)
+
+// oneTimeTokenBytes es la entropía de los tokens de un solo uso enviados al usuario
+const oneTimeTokenBytes = 32
+
+// newOneTimeToken genera un token aleatorio y el registro (con su hash) que se persiste.
+// El valor en claro solo se envía al usuario y nunca se guarda.
+func newOneTimeToken(purpose domain.OneTimeTokenPurpose, userID string, ttl time.Duration) (string, *domain.OneTimeToken, error) {
+	token, err := newRandomToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	return token, &domain.OneTimeToken{
+		ID:        uuid.NewString(),
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+// newRandomToken genera un valor aleatorio de oneTimeTokenBytes codificado en base64url
+func newRandomToken() (string, error) {
+	raw := make([]byte, oneTimeTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate one-time token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPasskeyName   = "Passkey"
+	maxPasskeyNameLength = 100
+)
+
+// PasskeyServiceConfig contiene la configuración del relying party WebAuthn
+type PasskeyServiceConfig struct {
+	// RPID es el dominio al que quedan ligadas las passkeys (sin esquema ni puerto)
+	RPID          string
+	RPDisplayName string
+	// RPOrigins son los orígenes completos desde los que se aceptan las ceremonias
+	RPOrigins []string
+	// Attestation es la preferencia de attestation: none, indirect, direct o enterprise
+	Attestation string
+	// SessionTTL es el tiempo máximo entre el inicio y el final de una ceremonia
+	SessionTTL time.Duration
+}
+
+type passkeyService struct {
+	webAuthn    *webauthn.WebAuthn
+	userService ports.UserService
+	repo        ports.PasskeyRepository
+	sessions    ports.WebAuthnSessionRepository
+	auditLogger ports.AuditLogger
+	sessionTTL  time.Duration
+}
+
+// NewPasskeyService crea una nueva instancia del servicio de passkeys
+func NewPasskeyService(
+	userService ports.UserService,
+	repo ports.PasskeyRepository,
+	sessions ports.WebAuthnSessionRepository,
+	auditLogger ports.AuditLogger,
+	config PasskeyServiceConfig,
+) (ports.PasskeyService, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    config.SessionTTL,
+		TimeoutUVD: config.SessionTTL,
+	}
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:                  config.RPID,
+		RPDisplayName:         config.RPDisplayName,
+		RPOrigins:             config.RPOrigins,
+		AttestationPreference: protocol.ConveyancePreference(config.Attestation),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementPreferred,
+			RequireResidentKey: protocol.ResidentKeyNotRequired(),
+			UserVerification:   protocol.VerificationPreferred,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn configuration: %w", err)
+	}
+
+	return &passkeyService{
+		webAuthn:    webAuthn,
+		userService: userService,
+		repo:        repo,
+		sessions:    sessions,
+		auditLogger: auditLogger,
+		sessionTTL:  config.SessionTTL,
+	}, nil
+}
+
+// BeginRegistration inicia el registro de una passkey excluyendo las credenciales que el usuario ya tiene
+func (s *passkeyService) BeginRegistration(ctx context.Context, userID string) (*domain.WebAuthnOptionsResponse, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.passkeys))
+	for _, credential := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, data, err := s.webAuthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+
+	return s.startSession(ctx, domain.WebAuthnRegistration, userID, data, creation.Response)
+}
+
+// FinishRegistration verifica la respuesta del autenticador y guarda la nueva passkey
+func (s *passkeyService) FinishRegistration(ctx context.Context, userID string, req domain.PasskeyRegistrationRequest) (*domain.PasskeyInfo, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	if len(name) > maxPasskeyNameLength {
+		validation := domain.NewValidationError()
+		validation.Add("name", fmt.Sprintf("must be at most %d characters", maxPasskeyNameLength))
+		return nil, validation
+	}
+
+	data, err := s.consumeSession(ctx, req.SessionID, domain.WebAuthnRegistration, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(req.Credential))
+	if err != nil {
+		return nil, invalidPasskeyCredentialError(err)
+	}
+	credential, err := s.webAuthn.CreateCredential(user, *data, parsed)
+	if err != nil {
+		return nil, invalidPasskeyCredentialError(err)
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	passkey := &domain.Passkey{
+		ID:              uuid.NewString(),
+		UserID:          userID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		SignCount:       credential.Authenticator.SignCount,
+		AAGUID:          credential.Authenticator.AAGUID,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Name:            name,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.repo.Create(ctx, passkey); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, domain.AuthEventPasskeyRegistered, userID, "passkey "+passkey.ID+" registered")
+	return toPasskeyInfo(passkey), nil
+}
+
+// List retorna las passkeys registradas por el usuario
+func (s *passkeyService) List(ctx context.Context, userID string) (*domain.PasskeysResponse, error) {
+	passkeys, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]domain.PasskeyInfo, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		infos = append(infos, *toPasskeyInfo(passkey))
+	}
+	return &domain.PasskeysResponse{Passkeys: infos}, nil
+}
+
+// Delete elimina una passkey del usuario
+func (s *passkeyService) Delete(ctx context.Context, userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrPasskeyNotFound
+	}
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.record(ctx, domain.AuthEventPasskeyRemoved, userID, "passkey "+id+" removed")
+	return nil
+}
+
+// BeginLogin inicia un login sin contraseña: el autenticador elige una passkey detectable del RP
+func (s *passkeyService) BeginLogin(ctx context.Context) (*domain.WebAuthnOptionsResponse, error) {
+	assertion, data, err := s.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+
+	return s.startSession(ctx, domain.WebAuthnLogin, "", data, assertion.Response)
+}
+
+// FinishLogin verifica la aserción de un login sin contraseña y retorna el usuario al que pertenece la passkey
+func (s *passkeyService) FinishLogin(ctx context.Context, req domain.PasskeyAssertionRequest) (*domain.PasskeyAssertion, error) {
+	data, err := s.consumeSession(ctx, req.SessionID, domain.WebAuthnLogin, "")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(req.Credential))
+	if err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	// El usuario se identifica por el user handle que devuelve el autenticador
+	var owner *webAuthnUser
+	credential, err := s.webAuthn.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+		user, err := s.loadUser(ctx, string(userHandle))
+		if err != nil {
+			return nil, err
+		}
+		owner = user
+		return user, nil
+	}, *data, parsed)
+	if err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	return s.recordUse(ctx, owner, credential)
+}
+
+// BeginVerification inicia la verificación de una passkey del usuario como segundo factor
+func (s *passkeyService) BeginVerification(ctx context.Context, userID string) (*domain.WebAuthnOptionsResponse, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(user.passkeys) == 0 {
+		return nil, domain.ErrPasskeyNotFound
+	}
+
+	assertion, data, err := s.webAuthn.BeginLogin(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey verification: %w", err)
+	}
+
+	return s.startSession(ctx, domain.WebAuthnMFA, userID, data, assertion.Response)
+}
+
+// Verify comprueba la aserción de una passkey del usuario iniciada con BeginVerification
+func (s *passkeyService) Verify(ctx context.Context, userID string, req domain.PasskeyAssertionRequest) (*domain.PasskeyAssertion, error) {
+	data, err := s.consumeSession(ctx, req.SessionID, domain.WebAuthnMFA, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(req.Credential))
+	if err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+	credential, err := s.webAuthn.ValidateLogin(user, *data, parsed)
+	if err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	return s.recordUse(ctx, user, credential)
+}
+
+// recordUse guarda el nuevo contador de firmas. Un contador que no avanza indica un autenticador clonado
+// y la aserción se rechaza.
+func (s *passkeyService) recordUse(ctx context.Context, user *webAuthnUser, credential *webauthn.Credential) (*domain.PasskeyAssertion, error) {
+	var passkey *domain.Passkey
+	for _, candidate := range user.passkeys {
+		if bytes.Equal(candidate.CredentialID, credential.ID) {
+			passkey = candidate
+			break
+		}
+	}
+	if passkey == nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	if credential.Authenticator.CloneWarning {
+		s.record(ctx, domain.AuthEventPasskeyCloneDetected, user.user.ID, "sign counter of passkey "+passkey.ID+" did not increase")
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	if err := s.repo.RecordUse(ctx, passkey.ID, credential.Authenticator.SignCount, credential.Flags.BackupState, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &domain.PasskeyAssertion{
+		UserID:       user.user.ID,
+		UserVerified: credential.Flags.UserVerified,
+	}, nil
+}
+
+// startSession guarda el estado de la ceremonia y retorna las opciones para el navegador
+func (s *passkeyService) startSession(ctx context.Context, ceremony domain.WebAuthnCeremony, userID string, data *webauthn.SessionData, options any) (*domain.WebAuthnOptionsResponse, error) {
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webauthn session: %w", err)
+	}
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webauthn options: %w", err)
+	}
+
+	session := &domain.WebAuthnSession{
+		ID:        uuid.NewString(),
+		Ceremony:  ceremony,
+		UserID:    userID,
+		Data:      encodedData,
+		ExpiresAt: time.Now().Add(s.sessionTTL),
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &domain.WebAuthnOptionsResponse{
+		SessionID: session.ID,
+		Options:   encodedOptions,
+	}, nil
+}
+
+// consumeSession recupera (una sola vez) el estado de la ceremonia y comprueba que pertenece al usuario
+func (s *passkeyService) consumeSession(ctx context.Context, sessionID string, ceremony domain.WebAuthnCeremony, userID string) (*webauthn.SessionData, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return nil, domain.ErrWebAuthnSessionNotFound
+	}
+
+	session, err := s.sessions.Consume(ctx, sessionID, ceremony)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, domain.ErrWebAuthnSessionNotFound
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(session.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode webauthn session: %w", err)
+	}
+	return &data, nil
+}
+
+func (s *passkeyService) loadUser(ctx context.Context, userID string) (*webAuthnUser, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnUser{user: user, passkeys: passkeys}, nil
+}
+
+func (s *passkeyService) record(ctx context.Context, eventType domain.AuthEventType, userID, reason string) {
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       eventType,
+		UserID:     userID,
+		Reason:     reason,
+		OccurredAt: time.Now(),
+	})
+}
+
+// webAuthnUser adapta un usuario y sus passkeys a la interfaz que espera la librería WebAuthn.
+// El user handle es el ID del usuario.
+type webAuthnUser struct {
+	user     *domain.User
+	passkeys []*domain.Passkey
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(u.user.ID)
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	displayName := strings.TrimSpace(u.user.FirstName + " " + u.user.LastName)
+	if displayName == "" {
+		return u.user.NickName
+	}
+	return displayName
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(passkey.Transports))
+		for _, transport := range passkey.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              passkey.CredentialID,
+			PublicKey:       passkey.PublicKey,
+			AttestationType: passkey.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: passkey.BackupEligible,
+				BackupState:    passkey.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    passkey.AAGUID,
+				SignCount: passkey.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+func toPasskeyInfo(passkey *domain.Passkey) *domain.PasskeyInfo {
+	return &domain.PasskeyInfo{
+		ID:         passkey.ID,
+		Name:       passkey.Name,
+		Transports: passkey.Transports,
+		Synced:     passkey.BackupState,
+		CreatedAt:  passkey.CreatedAt,
+		LastUsedAt: passkey.LastUsedAt,
+	}
+}
+
+// invalidPasskeyCredentialError informa la credencial rechazada como error de validación
+func invalidPasskeyCredentialError(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		log.Printf("passkey registration rejected: %s (%s)", protocolErr.Details, protocolErr.DevInfo)
+	}
+
+	validation := domain.NewValidationError()
+	validation.Add("credential", "could not be verified")
+	return validation
+}
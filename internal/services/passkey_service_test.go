@@ -0,0 +1,612 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+)
+
+const (
+	testRPID   = "auth.example.com"
+	testOrigin = "https://auth.example.com"
+)
+
+func TestPasskeyRegistrationAndPasswordlessLogin(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	authenticator := newSoftwareAuthenticator(t, testRPID, testOrigin)
+
+	info := env.register(t, authenticator, "  YubiKey  ")
+	if info.Name != "YubiKey" {
+		t.Fatalf("name = %q, want YubiKey", info.Name)
+	}
+	if !env.audit.has(domain.AuthEventPasskeyRegistered) {
+		t.Fatal("passkey registration was not audited")
+	}
+
+	list, err := env.service.List(ctx, env.user.ID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list.Passkeys) != 1 || list.Passkeys[0].ID != info.ID {
+		t.Fatalf("passkeys = %+v, want the registered one", list.Passkeys)
+	}
+
+	options, err := env.service.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	assertion, err := env.service.FinishLogin(ctx, domain.PasskeyAssertionRequest{
+		SessionID:  options.SessionID,
+		Credential: authenticator.assert(t, options, true),
+	})
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if assertion.UserID != env.user.ID || !assertion.UserVerified {
+		t.Fatalf("assertion = %+v, want verified login of %s", assertion, env.user.ID)
+	}
+	if got := env.passkeys.get(info.ID).SignCount; got != authenticator.counter {
+		t.Fatalf("stored sign count = %d, want %d", got, authenticator.counter)
+	}
+
+	// La sesión de la ceremonia es de un solo uso
+	_, err = env.service.FinishLogin(ctx, domain.PasskeyAssertionRequest{
+		SessionID:  options.SessionID,
+		Credential: authenticator.assert(t, options, true),
+	})
+	if !errors.Is(err, domain.ErrWebAuthnSessionNotFound) {
+		t.Fatalf("reused ceremony error = %v, want ErrWebAuthnSessionNotFound", err)
+	}
+}
+
+func TestPasskeyLoginWithoutUserVerification(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	authenticator := newSoftwareAuthenticator(t, testRPID, testOrigin)
+	env.register(t, authenticator, "")
+
+	options, err := env.service.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	assertion, err := env.service.FinishLogin(ctx, domain.PasskeyAssertionRequest{
+		SessionID:  options.SessionID,
+		Credential: authenticator.assert(t, options, false),
+	})
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if assertion.UserVerified {
+		t.Fatal("assertion without the UV flag reported the user as verified")
+	}
+}
+
+func TestPasskeyRejectsForeignOriginAndUnknownCredential(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	registered := newSoftwareAuthenticator(t, testRPID, testOrigin)
+	env.register(t, registered, "")
+
+	// La passkey registrada usada desde una página de phishing
+	phishing := *registered
+	phishing.origin = "https://auth.example.com.evil.test"
+
+	// Una passkey sin registrar que presenta el user handle de un usuario existente
+	unregistered := newSoftwareAuthenticator(t, testRPID, testOrigin)
+	unregistered.userHandle = []byte(env.user.ID)
+
+	tests := []struct {
+		name          string
+		authenticator *softwareAuthenticator
+	}{
+		{name: "phishing origin", authenticator: &phishing},
+		{name: "unregistered credential", authenticator: unregistered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := env.service.BeginLogin(ctx)
+			if err != nil {
+				t.Fatalf("BeginLogin: %v", err)
+			}
+			_, err = env.service.FinishLogin(ctx, domain.PasskeyAssertionRequest{
+				SessionID:  options.SessionID,
+				Credential: tt.authenticator.assert(t, options, true),
+			})
+			if !errors.Is(err, domain.ErrInvalidPasskey) {
+				t.Fatalf("error = %v, want ErrInvalidPasskey", err)
+			}
+		})
+	}
+}
+
+func TestPasskeyCloneDetection(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	authenticator := newSoftwareAuthenticator(t, testRPID, testOrigin)
+	env.register(t, authenticator, "")
+
+	// Una copia del autenticador con el contador atrasado
+	cloned := *authenticator
+
+	login := func(a *softwareAuthenticator) error {
+		options, err := env.service.BeginLogin(ctx)
+		if err != nil {
+			t.Fatalf("BeginLogin: %v", err)
+		}
+		_, err = env.service.FinishLogin(ctx, domain.PasskeyAssertionRequest{
+			SessionID:  options.SessionID,
+			Credential: a.assert(t, options, true),
+		})
+		return err
+	}
+
+	if err := login(authenticator); err != nil {
+		t.Fatalf("login with the original authenticator: %v", err)
+	}
+	if err := login(&cloned); !errors.Is(err, domain.ErrInvalidPasskey) {
+		t.Fatalf("login with a stale sign counter error = %v, want ErrInvalidPasskey", err)
+	}
+	if !env.audit.has(domain.AuthEventPasskeyCloneDetected) {
+		t.Fatal("clone detection was not audited")
+	}
+}
+
+func TestPasskeySecondFactor(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.service.BeginVerification(ctx, env.user.ID); !errors.Is(err, domain.ErrPasskeyNotFound) {
+		t.Fatalf("BeginVerification without passkeys error = %v, want ErrPasskeyNotFound", err)
+	}
+
+	authenticator := newSoftwareAuthenticator(t, testRPID, testOrigin)
+	env.register(t, authenticator, "")
+
+	options, err := env.service.BeginVerification(ctx, env.user.ID)
+	if err != nil {
+		t.Fatalf("BeginVerification: %v", err)
+	}
+	var decoded struct {
+		AllowCredentials []struct {
+			ID string `json:"id"`
+		} `json:"allowCredentials"`
+	}
+	if err := json.Unmarshal(options.Options, &decoded); err != nil {
+		t.Fatalf("decode options: %v", err)
+	}
+	if len(decoded.AllowCredentials) != 1 || decoded.AllowCredentials[0].ID != encode(authenticator.credentialID) {
+		t.Fatalf("allowCredentials = %+v, want only the user's passkey", decoded.AllowCredentials)
+	}
+
+	// Otro usuario no puede terminar la ceremonia
+	_, err = env.service.Verify(ctx, uuid.NewString(), domain.PasskeyAssertionRequest{
+		SessionID:  options.SessionID,
+		Credential: authenticator.assert(t, options, false),
+	})
+	if !errors.Is(err, domain.ErrWebAuthnSessionNotFound) {
+		t.Fatalf("Verify by another user error = %v, want ErrWebAuthnSessionNotFound", err)
+	}
+
+	options, err = env.service.BeginVerification(ctx, env.user.ID)
+	if err != nil {
+		t.Fatalf("BeginVerification: %v", err)
+	}
+	assertion, err := env.service.Verify(ctx, env.user.ID, domain.PasskeyAssertionRequest{
+		SessionID:  options.SessionID,
+		Credential: authenticator.assert(t, options, false),
+	})
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if assertion.UserID != env.user.ID {
+		t.Fatalf("assertion user = %s, want %s", assertion.UserID, env.user.ID)
+	}
+
+	// Una ceremonia de login no sirve para el segundo factor
+	loginOptions, err := env.service.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	_, err = env.service.Verify(ctx, env.user.ID, domain.PasskeyAssertionRequest{
+		SessionID:  loginOptions.SessionID,
+		Credential: authenticator.assert(t, loginOptions, false),
+	})
+	if !errors.Is(err, domain.ErrWebAuthnSessionNotFound) {
+		t.Fatalf("Verify with a login ceremony error = %v, want ErrWebAuthnSessionNotFound", err)
+	}
+}
+
+func TestPasskeyRegistrationRejectsDuplicateCredential(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	authenticator := newSoftwareAuthenticator(t, testRPID, testOrigin)
+	env.register(t, authenticator, "")
+
+	options, err := env.service.BeginRegistration(ctx, env.user.ID)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	var decoded struct {
+		ExcludeCredentials []struct {
+			ID string `json:"id"`
+		} `json:"excludeCredentials"`
+	}
+	if err := json.Unmarshal(options.Options, &decoded); err != nil {
+		t.Fatalf("decode options: %v", err)
+	}
+	if len(decoded.ExcludeCredentials) != 1 || decoded.ExcludeCredentials[0].ID != encode(authenticator.credentialID) {
+		t.Fatalf("excludeCredentials = %+v, want the registered passkey", decoded.ExcludeCredentials)
+	}
+
+	_, err = env.service.FinishRegistration(ctx, env.user.ID, domain.PasskeyRegistrationRequest{
+		SessionID:  options.SessionID,
+		Credential: authenticator.attest(t, options),
+	})
+	if !errors.Is(err, domain.ErrPasskeyAlreadyRegistered) {
+		t.Fatalf("error = %v, want ErrPasskeyAlreadyRegistered", err)
+	}
+}
+
+type passkeyTestEnv struct {
+	service  ports.PasskeyService
+	user     *domain.User
+	passkeys *memoryPasskeyRepository
+	audit    *recordingAuditLogger
+}
+
+func newPasskeyTestEnv(t *testing.T) *passkeyTestEnv {
+	t.Helper()
+
+	user := &domain.User{
+		ID:        uuid.NewString(),
+		Email:     "ana@example.com",
+		NickName:  "ana",
+		FirstName: "Ana",
+		IsActive:  true,
+	}
+	passkeys := &memoryPasskeyRepository{}
+	audit := &recordingAuditLogger{}
+
+	service, err := NewPasskeyService(
+		&stubUserService{users: map[string]*domain.User{user.ID: user}},
+		passkeys,
+		&memoryWebAuthnSessionRepository{sessions: make(map[string]*domain.WebAuthnSession)},
+		audit,
+		PasskeyServiceConfig{
+			RPID:          testRPID,
+			RPDisplayName: "Bikes2Road",
+			RPOrigins:     []string{testOrigin},
+			Attestation:   "none",
+			SessionTTL:    5 * time.Minute,
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewPasskeyService: %v", err)
+	}
+
+	return &passkeyTestEnv{service: service, user: user, passkeys: passkeys, audit: audit}
+}
+
+// register completa la ceremonia de registro de la passkey del autenticador
+func (e *passkeyTestEnv) register(t *testing.T, authenticator *softwareAuthenticator, name string) *domain.PasskeyInfo {
+	t.Helper()
+	ctx := context.Background()
+
+	options, err := e.service.BeginRegistration(ctx, e.user.ID)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	info, err := e.service.FinishRegistration(ctx, e.user.ID, domain.PasskeyRegistrationRequest{
+		SessionID:  options.SessionID,
+		Name:       name,
+		Credential: authenticator.attest(t, options),
+	})
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return info
+}
+
+// softwareAuthenticator implementa en memoria un autenticador WebAuthn con una clave ES256
+// y attestation "none", como el que usaría un navegador con una passkey.
+type softwareAuthenticator struct {
+	rpID         string
+	origin       string
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	counter      uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T, rpID, origin string) *softwareAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatalf("generate credential id: %v", err)
+	}
+	return &softwareAuthenticator{rpID: rpID, origin: origin, key: key, credentialID: credentialID}
+}
+
+// attest responde a navigator.credentials.create() con las opciones de BeginRegistration
+func (a *softwareAuthenticator) attest(t *testing.T, options *domain.WebAuthnOptionsResponse) json.RawMessage {
+	t.Helper()
+
+	var creation struct {
+		Challenge string `json:"challenge"`
+		User      struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(options.Options, &creation); err != nil {
+		t.Fatalf("decode creation options: %v", err)
+	}
+	userHandle, err := base64.RawURLEncoding.DecodeString(creation.User.ID)
+	if err != nil {
+		t.Fatalf("decode user handle: %v", err)
+	}
+	a.userHandle = userHandle
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatalf("encode public key: %v", err)
+	}
+
+	// Datos del autenticador con la credencial adjunta: AAGUID a cero, longitud del ID, ID y clave COSE
+	authData := a.authenticatorData(0x01|0x04|0x40, 0)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatalf("encode attestation object: %v", err)
+	}
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    encode(a.clientData(t, "webauthn.create", creation.Challenge)),
+		"attestationObject": encode(attestationObject),
+	})
+}
+
+// assert responde a navigator.credentials.get(); userVerified indica si se comprobó el PIN o la biometría
+func (a *softwareAuthenticator) assert(t *testing.T, options *domain.WebAuthnOptionsResponse, userVerified bool) json.RawMessage {
+	t.Helper()
+
+	var request struct {
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(options.Options, &request); err != nil {
+		t.Fatalf("decode assertion options: %v", err)
+	}
+
+	flags := byte(0x01)
+	if userVerified {
+		flags |= 0x04
+	}
+	a.counter++
+	authData := a.authenticatorData(flags, a.counter)
+	clientData := a.clientData(t, "webauthn.get", request.Challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("sign assertion: %v", err)
+	}
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(a.userHandle),
+	})
+}
+
+func (a *softwareAuthenticator) authenticatorData(flags byte, counter uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, counter)
+}
+
+func (a *softwareAuthenticator) clientData(t *testing.T, ceremony, challenge string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		t.Fatalf("encode client data: %v", err)
+	}
+	return data
+}
+
+func (a *softwareAuthenticator) credential(t *testing.T, response map[string]string) json.RawMessage {
+	t.Helper()
+	credential, err := json.Marshal(map[string]any{
+		"id":       encode(a.credentialID),
+		"rawId":    encode(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatalf("encode credential: %v", err)
+	}
+	return credential
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// stubUserService resuelve usuarios desde un mapa; el resto de métodos no se usan en estos tests
+type stubUserService struct {
+	ports.UserService
+	users map[string]*domain.User
+}
+
+func (s *stubUserService) GetUserByID(_ context.Context, id string) (*domain.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+type memoryPasskeyRepository struct {
+	mu       sync.Mutex
+	passkeys []*domain.Passkey
+}
+
+func (r *memoryPasskeyRepository) Create(_ context.Context, passkey *domain.Passkey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.passkeys {
+		if bytes.Equal(existing.CredentialID, passkey.CredentialID) {
+			return domain.ErrPasskeyAlreadyRegistered
+		}
+	}
+	stored := *passkey
+	r.passkeys = append(r.passkeys, &stored)
+	return nil
+}
+
+func (r *memoryPasskeyRepository) ListByUser(_ context.Context, userID string) ([]*domain.Passkey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	passkeys := make([]*domain.Passkey, 0)
+	for _, passkey := range r.passkeys {
+		if passkey.UserID == userID {
+			copied := *passkey
+			passkeys = append(passkeys, &copied)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *memoryPasskeyRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	passkeys, err := r.ListByUser(ctx, userID)
+	return len(passkeys), err
+}
+
+func (r *memoryPasskeyRepository) RecordUse(_ context.Context, id string, signCount uint32, backupState bool, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, passkey := range r.passkeys {
+		if passkey.ID == id {
+			passkey.SignCount = signCount
+			passkey.BackupState = backupState
+			passkey.LastUsedAt = &usedAt
+			return nil
+		}
+	}
+	return domain.ErrPasskeyNotFound
+}
+
+func (r *memoryPasskeyRepository) Delete(_ context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, passkey := range r.passkeys {
+		if passkey.ID == id && passkey.UserID == userID {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrPasskeyNotFound
+}
+
+func (r *memoryPasskeyRepository) get(id string) *domain.Passkey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, passkey := range r.passkeys {
+		if passkey.ID == id {
+			return passkey
+		}
+	}
+	return nil
+}
+
+type memoryWebAuthnSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*domain.WebAuthnSession
+}
+
+func (r *memoryWebAuthnSessionRepository) Create(_ context.Context, session *domain.WebAuthnSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memoryWebAuthnSessionRepository) Consume(_ context.Context, id string, ceremony domain.WebAuthnCeremony) (*domain.WebAuthnSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.Ceremony != ceremony || time.Now().After(session.ExpiresAt) {
+		return nil, domain.ErrWebAuthnSessionNotFound
+	}
+	delete(r.sessions, id)
+	return session, nil
+}
+
+func (r *memoryWebAuthnSessionRepository) DeleteExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+type recordingAuditLogger struct {
+	mu     sync.Mutex
+	events []domain.AuthEvent
+}
+
+func (l *recordingAuditLogger) Record(_ context.Context, event domain.AuthEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *recordingAuditLogger) has(eventType domain.AuthEventType) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, event := range l.events {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
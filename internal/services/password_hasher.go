@@ -0,0 +1,177 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bikes2road/authentication/internal/ports"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// HashAlgorithmArgon2id genera hashes argon2id en formato PHC ($argon2id$v=19$m=...,t=...,p=...$salt$hash)
+	HashAlgorithmArgon2id = "argon2id"
+	// HashAlgorithmBcrypt genera hashes bcrypt ($2a$, $2b$, $2y$)
+	HashAlgorithmBcrypt = "bcrypt"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var errUnknownHashFormat = errors.New("unknown password hash format")
+
+// PasswordHasherConfig contiene el algoritmo con el que se generan los hashes nuevos y sus parámetros
+type PasswordHasherConfig struct {
+	Algorithm  string
+	BcryptCost int
+	// Argon2Memory es la memoria usada por argon2id en KiB
+	Argon2Memory      uint32
+	Argon2Time        uint32
+	Argon2Parallelism uint8
+}
+
+// argon2Params son los parámetros codificados en un hash argon2id
+type argon2Params struct {
+	memory      uint32
+	time        uint32
+	parallelism uint8
+}
+
+type passwordHasher struct {
+	config PasswordHasherConfig
+}
+
+// NewPasswordHasher crea un PasswordHasher que genera hashes con el algoritmo configurado
+// y verifica hashes argon2id y bcrypt
+func NewPasswordHasher(config PasswordHasherConfig) (ports.PasswordHasher, error) {
+	switch config.Algorithm {
+	case HashAlgorithmArgon2id:
+		if config.Argon2Memory == 0 || config.Argon2Time == 0 || config.Argon2Parallelism == 0 {
+			return nil, fmt.Errorf("argon2id memory, time and parallelism must be greater than zero")
+		}
+	case HashAlgorithmBcrypt:
+		if config.BcryptCost < bcrypt.MinCost || config.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", config.Algorithm)
+	}
+
+	return &passwordHasher{config: config}, nil
+}
+
+// Hash genera el hash de la contraseña con el algoritmo configurado
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.config.Algorithm == HashAlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	params := h.argon2Params()
+	key := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.time, params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify comprueba la contraseña contra un hash argon2id o bcrypt
+func (h *passwordHasher) Verify(hash, password string) (bool, error) {
+	switch {
+	case isBcryptHash(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to verify bcrypt hash: %w", err)
+		}
+		return true, nil
+	case strings.HasPrefix(hash, "$argon2id$"):
+		params, salt, key, err := decodeArgon2Hash(hash)
+		if err != nil {
+			return false, err
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, candidate) == 1, nil
+	default:
+		return false, errUnknownHashFormat
+	}
+}
+
+// NeedsRehash indica si el hash debe regenerarse con el algoritmo o los parámetros actuales
+func (h *passwordHasher) NeedsRehash(hash string) bool {
+	switch h.config.Algorithm {
+	case HashAlgorithmBcrypt:
+		if !isBcryptHash(hash) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.config.BcryptCost
+	default:
+		if !strings.HasPrefix(hash, "$argon2id$") {
+			return true
+		}
+		params, _, key, err := decodeArgon2Hash(hash)
+		return err != nil || params != h.argon2Params() || len(key) != argon2KeyLength
+	}
+}
+
+func (h *passwordHasher) argon2Params() argon2Params {
+	return argon2Params{
+		memory:      h.config.Argon2Memory,
+		time:        h.config.Argon2Time,
+		parallelism: h.config.Argon2Parallelism,
+	}
+}
+
+// isBcryptHash detecta los prefijos de las variantes de bcrypt
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// decodeArgon2Hash extrae parámetros, salt y clave de un hash argon2id en formato PHC
+func decodeArgon2Hash(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version: %s", parts[2])
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("invalid argon2 key")
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// PasswordServiceConfig contiene la configuración de la recuperación de contraseña
+type PasswordServiceConfig struct {
+	// ResetURL es la página del frontend a la que apunta el enlace de recuperación; se le añade ?token=...
+	ResetURL string
+	// ResetTokenTTL es la validez del enlace de recuperación
+	ResetTokenTTL time.Duration
+}
+
+type passwordService struct {
+	userService      ports.UserService
+	tokenRepo        ports.OneTimeTokenRepository
+	refreshTokenRepo ports.RefreshTokenRepository
+	revocationRepo   ports.TokenRevocationRepository
+	sessionRepo      ports.SessionRepository
+	mailer           ports.Mailer
+	auditLogger      ports.AuditLogger
+	events           ports.SecurityEventPublisher
+	transactor       ports.Transactor
+	config           PasswordServiceConfig
+}
+
+// NewPasswordService crea una nueva instancia del servicio de contraseñas
+func NewPasswordService(
+	userService ports.UserService,
+	tokenRepo ports.OneTimeTokenRepository,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	revocationRepo ports.TokenRevocationRepository,
+	sessionRepo ports.SessionRepository,
+	mailer ports.Mailer,
+	auditLogger ports.AuditLogger,
+	events ports.SecurityEventPublisher,
+	transactor ports.Transactor,
+	config PasswordServiceConfig,
+) ports.PasswordService {
+	return &passwordService{
+		userService:      userService,
+		tokenRepo:        tokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationRepo:   revocationRepo,
+		sessionRepo:      sessionRepo,
+		mailer:           mailer,
+		auditLogger:      auditLogger,
+		events:           events,
+		transactor:       transactor,
+		config:           config,
+	}
+}
+
+// ForgotPassword envía un enlace de recuperación si la cuenta existe.
+// El trabajo se hace en segundo plano para que la respuesta (y su duración) sea la misma exista o no la cuenta.
+func (s *passwordService) ForgotPassword(ctx context.Context, email string) error {
+	// GetUserByEmail no distingue mayúsculas, así que también encuentra las cuentas antiguas guardadas con ellas
+	email = strings.TrimSpace(email)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		if err := s.sendResetLink(ctx, email); err != nil {
+			log.Printf("failed to send password reset email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword consume el token de recuperación, aplica la nueva contraseña y cierra todas las sesiones del usuario
+func (s *passwordService) ResetPassword(ctx context.Context, token, password string) error {
+	// Validar la política antes de consumir el token para que el usuario pueda reintentar con el mismo enlace
+	if err := domain.ValidatePassword(password); err != nil {
+		validation := domain.NewValidationError()
+		validation.Add("password", err.Error())
+		return validation
+	}
+
+	record, err := s.tokenRepo.Consume(ctx, domain.PurposePasswordReset, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userService.GetUserByID(ctx, record.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsActive {
+		return domain.ErrUserInactive
+	}
+
+	// Abrir el enlace demuestra el control del buzón
+	user.EmailVerified = true
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userService.SetPassword(ctx, user, password); err != nil {
+			return err
+		}
+
+		if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, domain.PurposePasswordReset); err != nil {
+			return err
+		}
+
+		if err := revokeUserSessions(ctx, s.refreshTokenRepo, s.revocationRepo, s.sessionRepo, user.ID); err != nil {
+			return err
+		}
+
+		if err := s.events.Publish(ctx, domain.SecurityEvent{
+			Type:   domain.SecurityEventPasswordChanged,
+			UserID: user.ID,
+			Data:   map[string]string{"method": "reset"},
+		}); err != nil {
+			return err
+		}
+		return publishSessionsRevoked(ctx, s.events, user.ID, sessionsRevokedPasswordReset, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       domain.AuthEventPasswordReset,
+		UserID:     user.ID,
+		Reason:     "password reset with recovery link",
+		OccurredAt: time.Now(),
+	})
+
+	return nil
+}
+
+// sendResetLink emite un token de recuperación, invalidando los anteriores, y lo envía por email
+func (s *passwordService) sendResetLink(ctx context.Context, email string) error {
+	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	if err := s.tokenRepo.InvalidateForUser(ctx, user.ID, domain.PurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, record, err := newOneTimeToken(domain.PurposePasswordReset, user.ID, s.config.ResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokenRepo.Create(ctx, record); err != nil {
+		return err
+	}
+
+	link := s.config.ResetURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Restablece tu contraseña de Bikes2Road",
+		Body: fmt.Sprintf("Hola %s,\n\nHemos recibido una solicitud para restablecer tu contraseña. Ábrela desde este enlace:\n\n%s\n\nEl enlace caduca en %s y solo puede usarse una vez. Si no lo has solicitado, ignora este mensaje: tu contraseña no cambiará.\n",
+			user.FirstName, link, s.config.ResetTokenTTL),
+	})
+}
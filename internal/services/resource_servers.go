@@ -0,0 +1,41 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// resourceServerRegistry autentica a los resource servers registrados por configuración
+type resourceServerRegistry struct {
+	secretHashes map[string][sha256.Size]byte
+}
+
+// NewResourceServerRegistry crea el registro a partir de los pares client_id -> client_secret
+func NewResourceServerRegistry(clients map[string]string) ports.ResourceServerRegistry {
+	secretHashes := make(map[string][sha256.Size]byte, len(clients))
+	for clientID, secret := range clients {
+		secretHashes[clientID] = sha256.Sum256([]byte(secret))
+	}
+
+	return &resourceServerRegistry{
+		secretHashes: secretHashes,
+	}
+}
+
+// Authenticate verifica las credenciales de un resource server en tiempo constante
+func (r *resourceServerRegistry) Authenticate(clientID, clientSecret string) error {
+	presented := sha256.Sum256([]byte(clientSecret))
+	expected, ok := r.secretHashes[clientID]
+	if !ok {
+		// Comparar igualmente para no revelar qué client_id existen por tiempo de respuesta
+		expected = [sha256.Size]byte{}
+	}
+
+	if subtle.ConstantTimeCompare(presented[:], expected[:]) != 1 || !ok {
+		return domain.ErrInvalidClient
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// expiringRepository es cualquier almacén capaz de eliminar sus entradas expiradas
+type expiringRepository interface {
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// RunRevocationCleanup elimina periódicamente las revocaciones de tokens ya expirados.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunRevocationCleanup(ctx context.Context, repo ports.TokenRevocationRepository, interval time.Duration) {
+	runCleanup(ctx, "token revocations", repo, interval)
+}
+
+// RunOneTimeTokenCleanup elimina periódicamente los tokens de un solo uso expirados.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunOneTimeTokenCleanup(ctx context.Context, repo ports.OneTimeTokenRepository, interval time.Duration) {
+	runCleanup(ctx, "one-time tokens", repo, interval)
+}
+
+// RunWebAuthnSessionCleanup elimina periódicamente las ceremonias WebAuthn que no se completaron.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunWebAuthnSessionCleanup(ctx context.Context, repo ports.WebAuthnSessionRepository, interval time.Duration) {
+	runCleanup(ctx, "webauthn sessions", repo, interval)
+}
+
+// RunPhoneOTPCleanup elimina periódicamente los códigos de login por SMS expirados.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunPhoneOTPCleanup(ctx context.Context, repo ports.PhoneOTPRepository, interval time.Duration) {
+	runCleanup(ctx, "phone otps", repo, interval)
+}
+
+// RunSessionCleanup elimina periódicamente las sesiones cuyo último refresh token ya expiró.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunSessionCleanup(ctx context.Context, repo ports.SessionRepository, interval time.Duration) {
+	runCleanup(ctx, "sessions", repo, interval)
+}
+
+// RunSigningKeyCleanup elimina periódicamente las claves generadas cuyo periodo de gracia terminó.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunSigningKeyCleanup(ctx context.Context, repo ports.SigningKeyRepository, interval time.Duration) {
+	runCleanup(ctx, "signing keys", repo, interval)
+}
+
+// RunRateLimitCleanup descarta periódicamente los buckets de rate limiting ya recargados.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunRateLimitCleanup(ctx context.Context, limiter ports.RateLimiter, interval time.Duration) {
+	runCleanup(ctx, "rate limit buckets", limiter, interval)
+}
+
+func runCleanup(ctx context.Context, name string, repo expiringRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("failed to clean up expired %s: %v", name, err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("removed %d expired %s", deleted, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// secretCipher cifra con AES-256-GCM los secretos que deben guardarse recuperables (p. ej. secretos TOTP)
+type secretCipher struct {
+	aead cipher.AEAD
+}
+
+// newSecretCipher crea el cifrador a partir de una clave de 32 bytes codificada en base64
+func newSecretCipher(encodedKey string) (*secretCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes long, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &secretCipher{aead: aead}, nil
+}
+
+// Encrypt retorna nonce || ciphertext en base64; additionalData liga el secreto a su propietario
+func (c *secretCipher) Encrypt(plaintext, additionalData string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(additionalData))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt descifra un valor generado por Encrypt con el mismo additionalData
+func (c *secretCipher) Decrypt(encoded, additionalData string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted secret: %w", err)
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(additionalData))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
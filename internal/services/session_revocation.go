@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// Motivos del evento user.sessions.revoked
+const (
+	sessionsRevokedLogoutAll         = "logout_all"
+	sessionsRevokedRemoteSignOut     = "session_revoked"
+	sessionsRevokedPasswordChanged   = "password_changed"
+	sessionsRevokedPasswordReset     = "password_reset"
+	sessionsRevokedRefreshTokenReuse = "refresh_token_reuse"
+	sessionsRevokedUserDeactivated   = "user_deactivated"
+)
+
+// revokeUserSessions cierra todas las sesiones del usuario, revoca sus refresh tokens y todos los tokens emitidos hasta ahora.
+// iat tiene precisión de segundos: la revocación llega hasta el segundo anterior para que los tokens emitidos
+// a continuación (un nuevo login o los de la sesión que cambia la contraseña) no queden revocados. Los emitidos
+// antes dentro del mismo segundo se rechazan igualmente porque su sesión queda cerrada.
+func revokeUserSessions(
+	ctx context.Context,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	revocationRepo ports.TokenRevocationRepository,
+	sessionRepo ports.SessionRepository,
+	userID string,
+) error {
+	issuedBefore := time.Now().Truncate(time.Second).Add(-time.Nanosecond)
+
+	if err := sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	if err := refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if err := revocationRepo.RevokeAllForUser(ctx, userID, issuedBefore); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	return nil
+}
+
+// publishSessionsRevoked notifica el cierre de sesiones del usuario; sin sessionID se cerraron todas
+func publishSessionsRevoked(ctx context.Context, events ports.SecurityEventPublisher, userID, reason, sessionID string) error {
+	data := map[string]string{"reason": reason}
+	if sessionID != "" {
+		data["session_id"] = sessionID
+	}
+	return events.Publish(ctx, domain.SecurityEvent{
+		Type:   domain.SecurityEventSessionsRevoked,
+		UserID: userID,
+		Data:   data,
+	})
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/google/uuid"
+)
+
+type sessionService struct {
+	repo             ports.SessionRepository
+	refreshTokenRepo ports.RefreshTokenRepository
+	auditLogger      ports.AuditLogger
+	events           ports.SecurityEventPublisher
+	transactor       ports.Transactor
+}
+
+// NewSessionService crea una nueva instancia del servicio de sesiones
+func NewSessionService(
+	repo ports.SessionRepository,
+	refreshTokenRepo ports.RefreshTokenRepository,
+	auditLogger ports.AuditLogger,
+	events ports.SecurityEventPublisher,
+	transactor ports.Transactor,
+) ports.SessionService {
+	return &sessionService{
+		repo:             repo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditLogger:      auditLogger,
+		events:           events,
+		transactor:       transactor,
+	}
+}
+
+// List retorna las sesiones activas del usuario marcando la del token con el que se consulta
+func (s *sessionService) List(ctx context.Context, userID, currentSessionID string) (*domain.SessionsResponse, error) {
+	sessions, err := s.repo.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]domain.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		methods := session.Methods
+		if methods == nil {
+			methods = []string{}
+		}
+		infos = append(infos, domain.SessionInfo{
+			ID:         session.ID,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			AMR:        methods,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return &domain.SessionsResponse{Sessions: infos}, nil
+}
+
+// Revoke cierra una sesión del usuario. Sus access tokens dejan de validar de inmediato
+// y su familia de refresh tokens queda revocada.
+func (s *sessionService) Revoke(ctx context.Context, userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrSessionNotFound
+	}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Revoke(ctx, userID, id); err != nil {
+			return err
+		}
+
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, id); err != nil {
+			return fmt.Errorf("failed to revoke session refresh tokens: %w", err)
+		}
+
+		return publishSessionsRevoked(ctx, s.events, userID, sessionsRevokedRemoteSignOut, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditLogger.Record(ctx, domain.AuthEvent{
+		Type:       domain.AuthEventSessionRevoked,
+		UserID:     userID,
+		Reason:     "session " + id + " revoked",
+		OccurredAt: time.Now(),
+	})
+	return nil
+}
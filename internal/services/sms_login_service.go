@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+)
+
+// smsCodeDigits es la longitud de los códigos de login enviados por SMS
+const smsCodeDigits = 6
+
+// SMSLoginServiceConfig contiene la configuración del login con códigos enviados por SMS
+type SMSLoginServiceConfig struct {
+	// CodeTTL es la validez de cada código
+	CodeTTL time.Duration
+	// MaxAttempts es el número de verificaciones admitidas por código
+	MaxAttempts int
+	// PhoneLimit limita los códigos enviados a un mismo teléfono; sin configurar no se aplica
+	PhoneLimit domain.RateLimit
+}
+
+type smsLoginService struct {
+	userService ports.UserService
+	repo        ports.PhoneOTPRepository
+	sender      ports.SMSSender
+	limiter     ports.RateLimiter
+	config      SMSLoginServiceConfig
+}
+
+// NewSMSLoginService crea una nueva instancia del servicio de códigos de login por SMS
+func NewSMSLoginService(
+	userService ports.UserService,
+	repo ports.PhoneOTPRepository,
+	sender ports.SMSSender,
+	limiter ports.RateLimiter,
+	config SMSLoginServiceConfig,
+) ports.SMSLoginService {
+	return &smsLoginService{
+		userService: userService,
+		repo:        repo,
+		sender:      sender,
+		limiter:     limiter,
+		config:      config,
+	}
+}
+
+// Request envía un código al teléfono si pertenece a una cuenta activa.
+// El envío se hace en segundo plano para que la respuesta (y su duración) sea la misma exista o no la cuenta.
+func (s *smsLoginService) Request(ctx context.Context, phoneNumber string) error {
+	phoneNumber, err := domain.NormalizePhoneNumber(phoneNumber)
+	if err != nil {
+		validation := domain.NewValidationError()
+		validation.Add("phone_number", err.Error())
+		return validation
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		if err := s.sendCode(ctx, phoneNumber); err != nil {
+			log.Printf("failed to send sms login code: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Verify comprueba el código enviado al teléfono actual del usuario y lo consume
+func (s *smsLoginService) Verify(ctx context.Context, user *domain.User, code string) error {
+	otp, err := s.repo.ReserveAttempt(ctx, user.ID, s.config.MaxAttempts)
+	if err != nil {
+		return err
+	}
+
+	codeHash := smsCodeHash(user.ID, code)
+	if otp.PhoneNumber != user.PhoneNumber || subtle.ConstantTimeCompare([]byte(codeHash), []byte(otp.CodeHash)) != 1 {
+		return domain.ErrInvalidSMSCode
+	}
+
+	return s.repo.Consume(ctx, user.ID, codeHash)
+}
+
+// sendCode genera un código, sustituyendo al anterior, y lo envía respetando el límite por teléfono
+func (s *smsLoginService) sendCode(ctx context.Context, phoneNumber string) error {
+	// El límite se aplica exista o no la cuenta y sin avisar al cliente, que siempre recibe la misma respuesta
+	if s.config.PhoneLimit.Enabled() {
+		result, err := s.limiter.Allow(ctx, "sms-login:"+hashToken(phoneNumber), s.config.PhoneLimit)
+		if err != nil {
+			log.Printf("rate limiter unavailable, sending sms login code: %v", err)
+		} else if !result.Allowed {
+			return nil
+		}
+	}
+
+	user, err := s.userService.GetUserByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive {
+		return nil
+	}
+
+	code, err := newSMSCode()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.repo.Save(ctx, &domain.PhoneOTP{
+		UserID:      user.ID,
+		PhoneNumber: phoneNumber,
+		CodeHash:    smsCodeHash(user.ID, code),
+		ExpiresAt:   now.Add(s.config.CodeTTL),
+		CreatedAt:   now,
+	}); err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, domain.SMSMessage{
+		To: phoneNumber,
+		Body: fmt.Sprintf("Tu código de acceso a Bikes2Road es %s. Caduca en %s. No lo compartas con nadie.",
+			code, s.config.CodeTTL),
+	})
+}
+
+// newSMSCode genera un código numérico aleatorio de smsCodeDigits dígitos
+func newSMSCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < smsCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate sms code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", smsCodeDigits, n), nil
+}
+
+// smsCodeHash liga el código al usuario para que el mismo código no produzca el mismo hash en cuentas distintas
+func smsCodeHash(userID, code string) string {
+	return hashToken(userID + ":" + code)
+}
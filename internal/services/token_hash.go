@@ -0,0 +1,12 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// hashToken calcula el hash SHA-256 (hex) de un token opaco o JWT para persistirlo sin guardar su valor
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,25 +2,66 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/bikes2road/authentication/internal/domain"
 	"github.com/bikes2road/authentication/internal/ports"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 )
 
+const maxNameLength = 255
+
+var nickNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._]{3,30}$`)
+
 // userService implements the UserService port
 type userService struct {
-	repo ports.UserRepository
+	repo    ports.UserRepository
+	hasher  ports.PasswordHasher
+	lockout ports.LockoutService
 }
 
 // NewUserService creates a new instance of UserService
-func NewUserService(repo ports.UserRepository) ports.UserService {
+func NewUserService(repo ports.UserRepository, hasher ports.PasswordHasher, lockout ports.LockoutService) ports.UserService {
 	return &userService{
-		repo: repo,
+		repo:    repo,
+		hasher:  hasher,
+		lockout: lockout,
 	}
 }
 
+// GetUserByID retrieves a user by their ID
+func (s *userService) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByEmail retrieves a user by their email address
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return user, nil
+}
+
+// GetUserByPhoneNumber retrieves a user by their E.164 phone number
+func (s *userService) GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (*domain.User, error) {
+	user, err := s.repo.GetByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by phone number: %w", err)
+	}
+	return user, nil
+}
+
 // GetUserByEmailOrNickName retrieves a user by their email or nick name
 func (s *userService) GetUserByEmailOrNickName(ctx context.Context, emailOrNickName string) (*domain.User, error) {
 	user, err := s.repo.GetByEmailOrNickName(ctx, emailOrNickName)
@@ -30,7 +71,56 @@ func (s *userService) GetUserByEmailOrNickName(ctx context.Context, emailOrNickN
 	return user, nil
 }
 
-// VerifyUser checks if the provided credentials are valid and returns user info
+// CreateUser persists a new user
+func (s *userService) CreateUser(ctx context.Context, user *domain.User) error {
+	if err := s.repo.Create(ctx, user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// UpdateUser persists changes to an existing user
+func (s *userService) UpdateUser(ctx context.Context, user *domain.User) error {
+	if err := s.repo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// SetPassword validates the password policy, stores its hash and marks the user as having a password.
+// Policy failures are returned as a *domain.ValidationError on the "password" field.
+func (s *userService) SetPassword(ctx context.Context, user *domain.User, password string) error {
+	if err := domain.ValidatePassword(password); err != nil {
+		validation := domain.NewValidationError()
+		validation.Add("password", err.Error())
+		return validation
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hash
+	user.HasPassword = true
+	return s.UpdateUser(ctx, user)
+}
+
+// ExistsByNickName checks if the nick name is already taken
+func (s *userService) ExistsByNickName(ctx context.Context, nickName string) (bool, error) {
+	exists, err := s.repo.ExistsByNickName(ctx, nickName)
+	if err != nil {
+		return false, fmt.Errorf("failed to check nick name: %w", err)
+	}
+	return exists, nil
+}
+
+// VerifyUser checks if the provided credentials are valid and returns user info.
+// Failed attempts count towards the account lockout. A locked account only returns a
+// *domain.AccountLockedError once the password is correct: a wrong password gets the same
+// domain.ErrInvalidCredentials as an unknown identifier, so the lockout does not reveal
+// which accounts exist.
+// Hashes generated with an outdated algorithm or parameters are upgraded in place.
 func (s *userService) VerifyUser(ctx context.Context, req ports.VerifyUserRequest) (*domain.User, error) {
 	user, err := s.repo.GetByEmailOrNickName(ctx, req.EmailOrNickName)
 	if err != nil {
@@ -42,9 +132,179 @@ func (s *userService) VerifyUser(ctx context.Context, req ports.VerifyUserReques
 		return nil, domain.ErrUserInactive
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+	var lockedErr *domain.AccountLockedError
+	if err := s.lockout.Check(ctx, user.ID); err != nil && !errors.As(err, &lockedErr) {
+		return nil, err
+	}
+
+	valid, err := s.hasher.Verify(user.Password, req.Password)
+	if err != nil || !valid {
+		// Failures while locked are not counted, so guessing cannot extend the lock
+		if lockedErr == nil {
+			if err := s.lockout.RecordFailure(ctx, user.ID); err != nil && !errors.As(err, &lockedErr) {
+				log.Printf("failed to record login failure for user %s: %v", user.ID, err)
+			}
+		}
 		return nil, domain.ErrInvalidCredentials
 	}
 
+	if lockedErr != nil {
+		return nil, lockedErr
+	}
+
+	s.lockout.RecordSuccess(ctx, user.ID)
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+
+	return user, nil
+}
+
+// UnlockUser clears the lockout of an existing account
+func (s *userService) UnlockUser(ctx context.Context, userID, actorID string) error {
+	if _, err := s.repo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	return s.lockout.Unlock(ctx, userID, actorID)
+}
+
+// rehashPassword upgrades the stored hash to the configured algorithm.
+// Failures are only logged: the login already succeeded and will retry the upgrade next time.
+func (s *userService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Printf("failed to rehash password for user %s: %v", user.ID, err)
+		return
+	}
+
+	previous := user.Password
+	user.Password = hash
+	if err := s.repo.Update(ctx, user); err != nil {
+		user.Password = previous
+		log.Printf("failed to store rehashed password for user %s: %v", user.ID, err)
+	}
+}
+
+// RegisterUser validates the registration request, hashes the password and creates the user.
+// Validation failures are returned as a *domain.ValidationError with one message per field.
+func (s *userService) RegisterUser(ctx context.Context, req domain.RegisterRequest) (*domain.User, error) {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.NickName = strings.TrimSpace(req.NickName)
+	req.FirstName = strings.TrimSpace(req.FirstName)
+	req.LastName = strings.TrimSpace(req.LastName)
+
+	validation := domain.NewValidationError()
+
+	if req.Email == "" {
+		validation.Add("email", "is required")
+	} else if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email || len(req.Email) > maxNameLength {
+		validation.Add("email", "must be a valid email address")
+	}
+
+	if req.NickName == "" {
+		validation.Add("nick_name", "is required")
+	} else if !nickNamePattern.MatchString(req.NickName) {
+		validation.Add("nick_name", "must be 3 to 30 characters long and contain only letters, digits, dots or underscores")
+	}
+
+	if req.FirstName == "" {
+		validation.Add("first_name", "is required")
+	} else if len(req.FirstName) > maxNameLength {
+		validation.Add("first_name", "is too long")
+	}
+
+	if req.LastName == "" {
+		validation.Add("last_name", "is required")
+	} else if len(req.LastName) > maxNameLength {
+		validation.Add("last_name", "is too long")
+	}
+
+	// Phone numbers are optional but, when given, stored in E.164 so they can be used to log in
+	if strings.TrimSpace(req.PhoneNumber) == "" {
+		req.PhoneNumber = ""
+	} else if phoneNumber, err := domain.NormalizePhoneNumber(req.PhoneNumber); err != nil {
+		validation.Add("phone_number", err.Error())
+	} else {
+		req.PhoneNumber = phoneNumber
+	}
+
+	if err := domain.ValidatePassword(req.Password); err != nil {
+		validation.Add("password", err.Error())
+	}
+
+	// Uniqueness is only checked for well-formed values to avoid needless queries
+	if _, invalid := validation.Fields["email"]; !invalid {
+		exists, err := s.repo.ExistsByEmail(ctx, req.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check email: %w", err)
+		}
+		if exists {
+			validation.Add("email", "is already registered")
+		}
+	}
+
+	if _, invalid := validation.Fields["nick_name"]; !invalid {
+		exists, err := s.repo.ExistsByNickName(ctx, req.NickName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check nick name: %w", err)
+		}
+		if exists {
+			validation.Add("nick_name", "is already taken")
+		}
+	}
+
+	if _, invalid := validation.Fields["phone_number"]; !invalid && req.PhoneNumber != "" {
+		exists, err := s.repo.ExistsByPhoneNumber(ctx, req.PhoneNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check phone number: %w", err)
+		}
+		if exists {
+			validation.Add("phone_number", "is already registered")
+		}
+	}
+
+	if validation.HasErrors() {
+		return nil, validation
+	}
+
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:          uuid.NewString(),
+		NickName:    req.NickName,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Email:       req.Email,
+		PhoneNumber: req.PhoneNumber,
+		Password:    hash,
+		HasPassword: true,
+		IsActive:    true,
+		Role:        domain.RoleUser,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := s.repo.Create(ctx, user); err != nil {
+		// A concurrent registration took the email, nick name or phone number between the check and the insert
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			validation.Add("email", "is already registered")
+			return nil, validation
+		}
+		if errors.Is(err, domain.ErrNickNameTaken) {
+			validation.Add("nick_name", "is already taken")
+			return nil, validation
+		}
+		if errors.Is(err, domain.ErrPhoneNumberAlreadyRegistered) {
+			validation.Add("phone_number", "is already registered")
+			return nil, validation
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
 	return user, nil
 }
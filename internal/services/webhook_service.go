@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bikes2road/authentication/internal/domain"
+	"github.com/bikes2road/authentication/internal/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	webhookSecretBytes         = 32
+	defaultWebhookDeliveryPage = 50
+	maxWebhookDeliveryPage     = 200
+	maxWebhookErrorLength      = 500
+)
+
+// WebhookServiceConfig contiene la configuración de las suscripciones y de la entrega de webhooks
+type WebhookServiceConfig struct {
+	// EncryptionKey es la clave AES-256 (base64) con la que se cifran los secretos de firma
+	EncryptionKey string
+	// MaxAttempts es el número de intentos tras el que una entrega pasa a dead
+	MaxAttempts int
+	// RetryBase es la espera tras el primer fallo; se duplica en cada reintento hasta RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// Lease es el tiempo durante el que una entrega reclamada no la toma otra réplica
+	Lease time.Duration
+	// BatchSize es el número máximo de entregas que se procesan en cada pasada
+	BatchSize int
+	// AllowInsecureURLs admite endpoints http (solo para desarrollo y pruebas)
+	AllowInsecureURLs bool
+}
+
+type webhookService struct {
+	repo   ports.WebhookRepository
+	sender ports.WebhookSender
+	cipher *secretCipher
+	config WebhookServiceConfig
+}
+
+// NewWebhookService crea una nueva instancia del servicio de webhooks
+func NewWebhookService(repo ports.WebhookRepository, sender ports.WebhookSender, config WebhookServiceConfig) (ports.WebhookService, error) {
+	cipher, err := newSecretCipher(config.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook encryption key: %w", err)
+	}
+
+	return &webhookService{
+		repo:   repo,
+		sender: sender,
+		cipher: cipher,
+		config: config,
+	}, nil
+}
+
+// CreateSubscription registra el endpoint y genera su secreto de firma, que solo se retorna en esta respuesta
+func (s *webhookService) CreateSubscription(ctx context.Context, req domain.CreateWebhookRequest) (*domain.WebhookSubscriptionCreated, error) {
+	validation := domain.NewValidationError()
+
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+		validation.Add("url", "must be an absolute http(s) URL")
+	} else if endpoint.Scheme != "https" && !s.config.AllowInsecureURLs {
+		validation.Add("url", "must use https")
+	} else if endpoint.User != nil {
+		validation.Add("url", "must not contain credentials")
+	}
+
+	eventTypes := make([]domain.SecurityEventType, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(domain.SecurityEventTypes, eventType) {
+			validation.Add("event_types", "contains an unknown event type: "+string(eventType))
+			break
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	if validation.HasErrors() {
+		return nil, validation
+	}
+
+	raw := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	secret := "whsec_" + base64.RawURLEncoding.EncodeToString(raw)
+
+	subscription := &domain.WebhookSubscription{
+		ID:         uuid.NewString(),
+		URL:        req.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  time.Now(),
+	}
+	subscription.Secret, err = s.cipher.Encrypt(secret, subscription.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return &domain.WebhookSubscriptionCreated{
+		WebhookSubscriptionInfo: webhookSubscriptionInfo(subscription),
+		Secret:                  secret,
+	}, nil
+}
+
+// ListSubscriptions retorna las suscripciones registradas sin sus secretos
+func (s *webhookService) ListSubscriptions(ctx context.Context) (*domain.WebhookSubscriptionsResponse, error) {
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]domain.WebhookSubscriptionInfo, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		infos = append(infos, webhookSubscriptionInfo(subscription))
+	}
+	return &domain.WebhookSubscriptionsResponse{Subscriptions: infos}, nil
+}
+
+// DeleteSubscription elimina la suscripción junto con sus entregas pendientes
+func (s *webhookService) DeleteSubscription(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrWebhookSubscriptionNotFound
+	}
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries retorna las entregas más recientes que cumplen los filtros
+func (s *webhookService) ListDeliveries(ctx context.Context, query domain.WebhookDeliveryQuery) (*domain.WebhookDeliveriesResponse, error) {
+	validation := domain.NewValidationError()
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultWebhookDeliveryPage
+	} else if limit < 0 || limit > maxWebhookDeliveryPage {
+		validation.Add("limit", "must be between 1 and "+strconv.Itoa(maxWebhookDeliveryPage))
+	}
+
+	switch query.Status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDead:
+	default:
+		validation.Add("status", "must be pending, delivered or dead")
+	}
+
+	if query.SubscriptionID != "" {
+		if _, err := uuid.Parse(query.SubscriptionID); err != nil {
+			validation.Add("subscription_id", "must be a valid id")
+		}
+	}
+
+	if validation.HasErrors() {
+		return nil, validation
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, query.Status, query.SubscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]domain.WebhookDeliveryInfo, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		infos = append(infos, domain.WebhookDeliveryInfo{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			LastError:      delivery.LastError,
+			LastStatusCode: delivery.LastStatusCode,
+			CreatedAt:      delivery.CreatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+		})
+	}
+	return &domain.WebhookDeliveriesResponse{Deliveries: infos}, nil
+}
+
+// Replay vuelve a poner en cola la entrega con los intentos a cero. Se reenvía el mismo evento,
+// con el mismo ID, para que el receptor pueda descartarlo si ya lo había procesado.
+func (s *webhookService) Replay(ctx context.Context, deliveryID string) error {
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		return domain.ErrWebhookDeliveryNotFound
+	}
+	return s.repo.Replay(ctx, deliveryID)
+}
+
+// Publish guarda en el outbox una entrega por cada suscripción al tipo de evento, en la transacción
+// del contexto si la hay. El envío lo hace el dispatcher, por lo que un receptor lento o caído no retrasa la operación.
+func (s *webhookService) Publish(ctx context.Context, event domain.SecurityEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode security event %s: %w", event.Type, err)
+	}
+
+	if _, err := s.repo.Enqueue(ctx, event, payload); err != nil {
+		return fmt.Errorf("failed to enqueue security event %s: %w", event.Type, err)
+	}
+	return nil
+}
+
+// DeliverDue envía las entregas cuyo intento ya venció. Las que fallan se reprograman con backoff
+// exponencial y, al agotar los intentos, pasan a dead hasta que se haga un replay.
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[string]*domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, domain.ErrWebhookSubscriptionNotFound) {
+				return 0, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		// La suscripción se eliminó después de reclamar la entrega; el borrado en cascada ya la descartó
+		if subscription == nil {
+			continue
+		}
+
+		statusCode, err := s.send(ctx, subscription, delivery)
+		s.recordAttempt(delivery, statusCode, err)
+
+		if err := s.repo.UpdateAttempt(ctx, delivery); err != nil {
+			log.Printf("failed to record webhook delivery %s: %v", delivery.ID, err)
+		}
+	}
+	return len(deliveries), nil
+}
+
+// send firma la entrega con el instante actual, de modo que cada reintento lleva un timestamp reciente
+func (s *webhookService) send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	secret, err := s.cipher.Decrypt(subscription.Secret, subscription.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	return s.sender.Send(ctx, domain.WebhookRequest{
+		URL:       subscription.URL,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		Timestamp: timestamp,
+		Signature: domain.SignWebhook(secret, timestamp, delivery.Payload),
+		Body:      delivery.Payload,
+	})
+}
+
+// recordAttempt actualiza el estado de la entrega con el resultado del intento
+func (s *webhookService) recordAttempt(delivery *domain.WebhookDelivery, statusCode int, sendErr error) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+
+	if sendErr == nil {
+		delivery.Status = domain.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = sendErr.Error()
+	if len(delivery.LastError) > maxWebhookErrorLength {
+		delivery.LastError = delivery.LastError[:maxWebhookErrorLength]
+	}
+
+	if delivery.Attempts >= s.config.MaxAttempts {
+		delivery.Status = domain.WebhookDeliveryDead
+		log.Printf("webhook delivery %s exhausted %d attempts: %v", delivery.ID, delivery.Attempts, sendErr)
+		return
+	}
+	delivery.NextAttemptAt = now.Add(s.retryDelay(delivery.Attempts))
+}
+
+// retryDelay retorna la espera antes del siguiente intento: RetryBase * 2^(intentos-1), como mucho RetryMax
+func (s *webhookService) retryDelay(attempts int) time.Duration {
+	delay := s.config.RetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.config.RetryMax {
+			return s.config.RetryMax
+		}
+	}
+	return min(delay, s.config.RetryMax)
+}
+
+func webhookSubscriptionInfo(subscription *domain.WebhookSubscription) domain.WebhookSubscriptionInfo {
+	return domain.WebhookSubscriptionInfo{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// RunWebhookDispatcher entrega periódicamente los webhooks pendientes del outbox.
+// Bloquea hasta que el contexto se cancele, por lo que debe ejecutarse en una goroutine.
+func RunWebhookDispatcher(ctx context.Context, service ports.WebhookService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Se sigue mientras queden entregas vencidas para vaciar el outbox sin esperar al siguiente tick
+			for {
+				processed, err := service.DeliverDue(ctx)
+				if err != nil {
+					log.Printf("failed to deliver webhooks: %v", err)
+					break
+				}
+				if processed == 0 || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}